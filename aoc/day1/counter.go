@@ -2,85 +2,270 @@ package day1
 
 // Counter defines a strategy for counting during dial rotations
 type Counter interface {
-	// Count processes a rotation and returns the count contribution
-	Count(rotation Rotation, position int) int
+	// Count processes a rotation starting at position on dial g and returns the count contribution
+	Count(rotation Rotation, position int, g Geometry) int
 }
 
-// EndPositionCounter counts only when the dial ends at position 0
+// EndPositionCounter counts only when the dial ends at the target position
 type EndPositionCounter struct{}
 
-func (EndPositionCounter) Count(rotation Rotation, position int) int {
-	if applyRotation(rotation, position) == 0 {
+func (EndPositionCounter) Count(rotation Rotation, position int, g Geometry) int {
+	if g.Apply(rotation, position) == g.Target {
 		return 1
 	}
 	return 0
 }
 
-// ZeroCrossingCounter counts every time the dial passes through 0
+// ZeroCrossingCounter counts every time the dial passes through the target position.
+// The name predates configurable targets; with the default geometry the target is 0.
 type ZeroCrossingCounter struct{}
 
-func (ZeroCrossingCounter) Count(rotation Rotation, position int) int {
-	return countZeroCrossings(rotation, position)
+func (ZeroCrossingCounter) Count(rotation Rotation, position int, g Geometry) int {
+	return g.Hits(rotation, position)
+}
+
+// PositionVisitCounter counts every click that lands on Position, whatever the dial's target
+type PositionVisitCounter struct {
+	Position int
+}
+
+func (c PositionVisitCounter) Count(rotation Rotation, position int, g Geometry) int {
+	return Geometry{Size: g.Size, Target: mod(c.Position, g.Size)}.Hits(rotation, position)
+}
+
+// NetRevolutionCounter counts wraps past the top of the dial (from Size-1 to 0), adding
+// one for each wrap to the right and subtracting one for each wrap to the left
+type NetRevolutionCounter struct{}
+
+func (NetRevolutionCounter) Count(rotation Rotation, position int, g Geometry) int {
+	return g.Wraps(rotation, position)
+}
+
+// MaxWrapDepthCounter tracks the furthest the dial gets from its starting revolution,
+// in net wraps either way. Its contribution is how much a rotation raises that maximum,
+// so the total is the maximum itself. It keeps state: use a new one for each dial.
+type MaxWrapDepthCounter struct {
+	level, deepest int
+}
+
+func (c *MaxWrapDepthCounter) Count(rotation Rotation, position int, g Geometry) int {
+	// Within a rotation the level moves monotonically, so its extreme is at the end
+	c.level += g.Wraps(rotation, position)
+	depth := c.level
+	if depth < 0 {
+		depth = -depth
+	}
+	if depth <= c.deepest {
+		return 0
+	}
+	gain := depth - c.deepest
+	c.deepest = depth
+	return gain
+}
+
+// Geometry describes the dial: how many positions it has and which one is counted
+type Geometry struct {
+	Size   int `json:"size"`   // number of positions, numbered 0 to Size-1
+	Target int `json:"target"` // position the counters look for
+}
+
+// Apply returns the position reached by rotating from position
+func (g Geometry) Apply(r Rotation, position int) int {
+	if r.Direction == 'L' {
+		return mod(position-r.Distance, g.Size)
+	}
+	return mod(position+r.Distance, g.Size)
+}
+
+// Hits counts the clicks of a rotation from position that land on the target, in O(1).
+//
+// The k-th click (1 <= k <= Distance) lands on position ± k, so it hits the target
+// when k is congruent to the signed offset from position to the target. The first
+// such k is that offset reduced into 1..Size, and every Size clicks after it hit again.
+func (g Geometry) Hits(r Rotation, position int) int {
+	offset := g.Target - position
+	if r.Direction == 'L' {
+		offset = -offset
+	}
+	first := mod(offset, g.Size)
+	if first == 0 {
+		first = g.Size // starting on the target only counts once we come back round
+	}
+	if r.Distance < first {
+		return 0
+	}
+	return 1 + (r.Distance-first)/g.Size
+}
+
+// Wraps returns the net number of times a rotation from position wraps past the top of
+// the dial: positive to the right (Size-1 to 0), negative to the left (0 to Size-1)
+func (g Geometry) Wraps(r Rotation, position int) int {
+	if r.Direction == 'L' {
+		return floorDiv(position-r.Distance, g.Size)
+	}
+	return floorDiv(position+r.Distance, g.Size)
+}
+
+// floorDiv divides a by a positive n, rounding towards negative infinity
+func floorDiv(a, n int) int {
+	return (a - mod(a, n)) / n
+}
+
+// mod returns the non-negative remainder of a divided by n
+func mod(a, n int) int {
+	a %= n
+	if a < 0 {
+		a += n
+	}
+	return a
+}
+
+// StepCounter counts target hits by moving the dial one click at a time. It is
+// O(distance) per rotation and exists as a reference to check (or Diff) faster counters.
+type StepCounter struct{}
+
+func (StepCounter) Count(rotation Rotation, position int, g Geometry) int {
+	delta := 1
+	if rotation.Direction == 'L' {
+		delta = -1
+	}
+	hits := 0
+	for i := 0; i < rotation.Distance; i++ {
+		position = mod(position+delta, g.Size)
+		if position == g.Target {
+			hits++
+		}
+	}
+	return hits
+}
+
+// NamedCounter pairs a Counter with the name its count is reported under
+type NamedCounter struct {
+	Name    string
+	Counter Counter
 }
 
 // Dial represents the safe's dial with a current position
 type Dial struct {
+	geometry Geometry
 	position int
-	counter  Counter
-	count    int
+	counters []NamedCounter
+	counts   []int
+	trace    *Trace // nil unless created WithTrace
 }
 
-// NewDial creates a dial starting at position 50 with the given counter strategy
-func NewDial(counter Counter) *Dial {
-	return &Dial{
+// DialOption configures a Dial created by NewDial or NewMultiDial
+type DialOption func(*Dial)
+
+// WithSize sets the number of positions on the dial (default 100)
+func WithSize(size int) DialOption {
+	return func(d *Dial) { d.geometry.Size = size }
+}
+
+// WithStart sets the starting position (default 50)
+func WithStart(position int) DialOption {
+	return func(d *Dial) { d.position = position }
+}
+
+// WithTarget sets the position the counter looks for (default 0)
+func WithTarget(position int) DialOption {
+	return func(d *Dial) { d.geometry.Target = position }
+}
+
+// NewDial creates a dial with the given counter strategy. Without options it is the
+// puzzle's dial: 100 positions, starting at 50, counting position 0. A size below 1
+// falls back to 100, and start and target positions are taken modulo the size.
+func NewDial(counter Counter, opts ...DialOption) *Dial {
+	return NewMultiDial([]NamedCounter{{Name: "count", Counter: counter}}, opts...)
+}
+
+// NewMultiDial creates a dial that runs several counters over the same rotations.
+// Names should be unique; Count reports the first counter.
+func NewMultiDial(counters []NamedCounter, opts ...DialOption) *Dial {
+	d := &Dial{
+		geometry: Geometry{Size: 100},
 		position: 50,
-		counter:  counter,
+		counters: counters,
+		counts:   make([]int, len(counters)),
 	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.geometry.Size < 1 {
+		d.geometry.Size = 100
+	}
+	d.position = mod(d.position, d.geometry.Size)
+	d.geometry.Target = mod(d.geometry.Target, d.geometry.Size)
+	if d.trace != nil {
+		d.trace.begin(d)
+	}
+	return d
 }
 
-// Rotate applies a rotation, updates the count, and returns the dial for chaining
+// Rotate applies a rotation, updates the counts, and returns the dial for chaining
 func (d *Dial) Rotate(r Rotation) *Dial {
-	d.count += d.counter.Count(r, d.position)
-	d.position = applyRotation(r, d.position)
+	var contributions []int
+	if d.trace != nil {
+		contributions = make([]int, len(d.counters))
+	}
+
+	for i, c := range d.counters {
+		n := c.Counter.Count(r, d.position, d.geometry)
+		d.counts[i] += n
+		if contributions != nil {
+			contributions[i] = n
+		}
+	}
+
+	before := d.position
+	d.position = d.geometry.Apply(r, d.position)
+
+	if d.trace != nil {
+		d.trace.Steps = append(d.trace.Steps, TraceStep{
+			Index:         len(d.trace.Steps),
+			Rotation:      r,
+			Before:        before,
+			After:         d.position,
+			Wraps:         d.geometry.Wraps(r, before),
+			Contributions: contributions,
+		})
+	}
 	return d
 }
 
-// Count returns the accumulated count
+// Count returns the accumulated count of the first counter
 func (d *Dial) Count() int {
-	return d.count
+	if len(d.counts) == 0 {
+		return 0
+	}
+	return d.counts[0]
 }
 
-// applyRotation applies a rotation to a position and returns the new position (0-99)
-func applyRotation(r Rotation, position int) int {
-	var newPos int
-	if r.Direction == 'L' {
-		newPos = position - r.Distance
-	} else { // 'R'
-		newPos = position + r.Distance
+// CountOf returns the accumulated count of the named counter
+func (d *Dial) CountOf(name string) (int, bool) {
+	for i, c := range d.counters {
+		if c.Name == name {
+			return d.counts[i], true
+		}
 	}
+	return 0, false
+}
 
-	// Normalize to 0-99 range
-	newPos = newPos % 100
-	if newPos < 0 {
-		newPos += 100
+// Counts returns every counter's accumulated count by name
+func (d *Dial) Counts() map[string]int {
+	counts := make(map[string]int, len(d.counters))
+	for i, c := range d.counters {
+		counts[c.Name] = d.counts[i]
 	}
-	return newPos
+	return counts
 }
 
-// countZeroCrossings counts how many times a rotation crosses position 0
-func countZeroCrossings(r Rotation, from int) int {
-	if r.Direction == 'L' {
-		if from == 0 {
-			// Starting at 0, count complete wraps
-			return r.Distance / 100
-		}
-		// Going left from position p, we hit 0 after p steps
-		if r.Distance >= from {
-			return 1 + (r.Distance-from)/100
-		}
-		return 0
-	} else { // 'R'
-		// Going right, we cross 0 every 100 steps starting from (100 - position)
-		return (from + r.Distance) / 100
-	}
+// Position returns the dial's current position
+func (d *Dial) Position() int {
+	return d.position
+}
+
+// Geometry returns the dial's size and target position
+func (d *Dial) Geometry() Geometry {
+	return d.geometry
 }
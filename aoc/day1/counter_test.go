@@ -0,0 +1,165 @@
+package day1
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// step moves one click at a time and reports the final position and how many
+// clicks landed on target.
+func step(r Rotation, position int, g Geometry) (end, hits int) {
+	delta := 1
+	if r.Direction == 'L' {
+		delta = -1
+	}
+	for i := 0; i < r.Distance; i++ {
+		position = mod(position+delta, g.Size)
+		if position == g.Target {
+			hits++
+		}
+	}
+	return position, hits
+}
+
+// TestGeometryMatchesStepping checks every start, target, direction and
+// distance (up to a few revolutions) on small dials.
+func TestGeometryMatchesStepping(t *testing.T) {
+	for size := 1; size <= 12; size++ {
+		for target := 0; target < size; target++ {
+			g := Geometry{Size: size, Target: target}
+			for start := 0; start < size; start++ {
+				for distance := 0; distance <= 3*size+1; distance++ {
+					for _, dir := range []rune{'L', 'R'} {
+						r := Rotation{Direction: dir, Distance: distance}
+						wantEnd, wantHits := step(r, start, g)
+						if got := g.Apply(r, start); got != wantEnd {
+							t.Fatalf("%+v: Apply(%v, %d) = %d, want %d", g, r, start, got, wantEnd)
+						}
+						if got := g.Hits(r, start); got != wantHits {
+							t.Fatalf("%+v: Hits(%v, %d) = %d, want %d", g, r, start, got, wantHits)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestDialOptions(t *testing.T) {
+	rotations := []Rotation{{'R', 7}, {'L', 3}, {'L', 12}, {'R', 25}, {'L', 1}}
+
+	for size := 1; size <= 9; size++ {
+		for start := 0; start < size; start++ {
+			for target := 0; target < size; target++ {
+				g := Geometry{Size: size, Target: target}
+				ends := NewDial(EndPositionCounter{}, WithSize(size), WithStart(start), WithTarget(target))
+				passes := NewDial(ZeroCrossingCounter{}, WithSize(size), WithStart(start), WithTarget(target))
+
+				position, wantEnds, wantPasses := start, 0, 0
+				for _, r := range rotations {
+					var hits int
+					position, hits = step(r, position, g)
+					wantPasses += hits
+					if position == target {
+						wantEnds++
+					}
+					ends.Rotate(r)
+					passes.Rotate(r)
+				}
+
+				if ends.Count() != wantEnds || passes.Count() != wantPasses || ends.Position() != position {
+					t.Fatalf("size %d start %d target %d: got ends=%d passes=%d position=%d, want %d %d %d",
+						size, start, target, ends.Count(), passes.Count(), ends.Position(), wantEnds, wantPasses, position)
+				}
+			}
+		}
+	}
+}
+
+func TestNewDialDefaults(t *testing.T) {
+	d := NewDial(EndPositionCounter{})
+	if d.Position() != 50 || d.Geometry() != (Geometry{Size: 100, Target: 0}) {
+		t.Errorf("default dial: position %d geometry %+v", d.Position(), d.Geometry())
+	}
+
+	d = NewDial(EndPositionCounter{}, WithSize(0), WithStart(-1), WithTarget(205))
+	if d.Position() != 99 || d.Geometry() != (Geometry{Size: 100, Target: 5}) {
+		t.Errorf("normalised dial: position %d geometry %+v", d.Position(), d.Geometry())
+	}
+}
+
+// TestNewCountersMatchStepping checks the visit, net revolution and wrap depth
+// counters against a dial stepped one click at a time.
+func TestNewCountersMatchStepping(t *testing.T) {
+	rotations := []Rotation{{'R', 13}, {'L', 40}, {'R', 2}, {'L', 9}, {'R', 31}, {'R', 0}, {'L', 17}}
+
+	for size := 1; size <= 8; size++ {
+		for start := 0; start < size; start++ {
+			for visit := 0; visit < size; visit++ {
+				depth := &MaxWrapDepthCounter{}
+				dial := NewMultiDial([]NamedCounter{
+					{Name: "visits", Counter: PositionVisitCounter{Position: visit}},
+					{Name: "net", Counter: NetRevolutionCounter{}},
+					{Name: "depth", Counter: depth},
+				}, WithSize(size), WithStart(start))
+
+				// unwound is the position without reducing modulo size
+				unwound, wantVisits, wantDepth := start, 0, 0
+				for _, r := range rotations {
+					delta := 1
+					if r.Direction == 'L' {
+						delta = -1
+					}
+					for i := 0; i < r.Distance; i++ {
+						unwound += delta
+						if mod(unwound, size) == visit {
+							wantVisits++
+						}
+						wantDepth = max(wantDepth, abs(floorDiv(unwound, size)))
+					}
+					dial.Rotate(r)
+				}
+
+				want := map[string]int{"visits": wantVisits, "net": floorDiv(unwound, size), "depth": wantDepth}
+				for name, w := range want {
+					if got, _ := dial.CountOf(name); got != w {
+						t.Fatalf("size %d start %d visit %d: %s = %d, want %d", size, start, visit, name, got, w)
+					}
+				}
+			}
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func TestSolveMatchesParts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	example := "L68\nL30\nR48\nL5\nR60\nL55\nL1\nL99\nR14\nL82\n"
+	if err := os.WriteFile(path, []byte(example), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Solve(path)
+	if err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+	for i, part := range Parts {
+		want, err := part(path)
+		if err != nil {
+			t.Fatalf("part %d: %v", i+1, err)
+		}
+		if got[i] != want {
+			t.Errorf("part %d: Solve = %d, Part = %d", i+1, got[i], want)
+		}
+	}
+	if got[0] != 3 || got[1] != 6 {
+		t.Errorf("Solve = %v, want [3 6]", got)
+	}
+}
@@ -1,4 +1,25 @@
 package day1
 
+import "fmt"
+
 // Parts contains all implemented parts for this day
 var Parts = []func(string) (int, error){Part1, Part2}
+
+// Solve answers every part in a single pass over the input, in part order
+func Solve(inputPath string) ([]int, error) {
+	dial := NewMultiDial([]NamedCounter{
+		{Name: "part1", Counter: EndPositionCounter{}},
+		{Name: "part2", Counter: ZeroCrossingCounter{}},
+	})
+
+	err := ProcessFile(inputPath, func(r Rotation) error {
+		dial.Rotate(r)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("processing rotations: %w", err)
+	}
+
+	counts := dial.Counts()
+	return []int{counts["part1"], counts["part2"]}, nil
+}
@@ -0,0 +1,77 @@
+package day1
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"adv2025/aoc/difftest"
+)
+
+// simulateSteps is the brute-force reference: it moves the dial one click at
+// a time from position 50 and counts every click that lands on 0.
+func simulateSteps(rotations []Rotation) (int, error) {
+	position, count := 50, 0
+	for _, r := range rotations {
+		step := 1
+		if r.Direction == 'L' {
+			step = -1
+		}
+		for i := 0; i < r.Distance; i++ {
+			position = (position + step + 100) % 100
+			if position == 0 {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func zeroCrossingDial(rotations []Rotation) (int, error) {
+	dial := NewDial(ZeroCrossingCounter{})
+	for _, r := range rotations {
+		dial.Rotate(r)
+	}
+	return dial.Count(), nil
+}
+
+var rotationProperty = difftest.Property[[]Rotation]{
+	Name:      "zero-crossings",
+	Reference: simulateSteps,
+	Candidate: zeroCrossingDial,
+	Generate: func(r *rand.Rand) []Rotation {
+		rotations := make([]Rotation, 1+r.Intn(20))
+		for i := range rotations {
+			rotations[i] = Rotation{Direction: rune("LR"[r.Intn(2)]), Distance: r.Intn(350)}
+		}
+		return rotations
+	},
+	Shrink: func(rotations []Rotation) [][]Rotation {
+		return difftest.ShrinkSlice(rotations, func(r Rotation) []Rotation {
+			var out []Rotation
+			for _, d := range difftest.ShrinkInt(r.Distance, 0) {
+				out = append(out, Rotation{Direction: r.Direction, Distance: d})
+			}
+			return out
+		})
+	},
+	Encode: func(rotations []Rotation) string {
+		var sb strings.Builder
+		for _, r := range rotations {
+			sb.WriteString(r.String() + "\n")
+		}
+		return sb.String()
+	},
+	Decode: func(s string) ([]Rotation, error) {
+		var rotations []Rotation
+		err := NewRotationParser(strings.NewReader(s)).Parse(func(r Rotation) error {
+			rotations = append(rotations, r)
+			return nil
+		})
+		return rotations, err
+	},
+}
+
+func TestZeroCrossingsMatchSimulation(t *testing.T) {
+	difftest.Check(t, rotationProperty)
+}
@@ -0,0 +1,24 @@
+package day1
+
+import "testing"
+
+func FuzzParseRotation(f *testing.F) {
+	for _, seed := range []string{"L68", "L30", "R48", "L5", "R60", "L55", "L1", "L99", "R14", "L82", "R1000", " L0 "} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		r, err := parseRotation(s)
+		if err != nil {
+			return
+		}
+
+		again, err := parseRotation(r.String())
+		if err != nil {
+			t.Fatalf("reparsing %q (from %q): %v", r.String(), s, err)
+		}
+		if again != r {
+			t.Fatalf("round trip of %q: got %+v, want %+v", s, again, r)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package day1
+
+// Analysis answers reverse questions about a fixed list of rotations for every
+// start position at once, instead of running a Dial per start.
+//
+// The trick is to unwind the dial. Let S_j be the signed sum of the first j rotations
+// (R positive). Rotation j then sweeps the clicks S_{j-1}+1..S_j (or S_j..S_{j-1}-1 to
+// the left), and from start s a click k lands on the target t exactly when k ≡ t-s
+// (mod Size). So every start is just a residue class ρ = t-s, and the hits of a
+// rotation are the clicks in its sweep congruent to ρ: Distance/Size for every class,
+// plus one for the Distance%Size classes that the leftover clicks cover. Adding those
+// leftovers to a difference array over residues gives all starts in O(m + Size).
+type Analysis struct {
+	geometry  Geometry
+	rotations []Rotation
+	offsets   []int // offsets[j] is S_j, the unwound displacement after j rotations
+}
+
+// NewAnalysis prepares the prefix sums for rotations on a dial with geometry g
+func NewAnalysis(rotations []Rotation, g Geometry) *Analysis {
+	if g.Size < 1 {
+		g.Size = 100
+	}
+	g.Target = mod(g.Target, g.Size)
+
+	offsets := make([]int, len(rotations)+1)
+	for j, r := range rotations {
+		offsets[j+1] = offsets[j] + signed(r)
+	}
+	return &Analysis{geometry: g, rotations: rotations, offsets: offsets}
+}
+
+// signed returns a rotation's displacement, positive to the right
+func signed(r Rotation) int {
+	if r.Direction == 'L' {
+		return -r.Distance
+	}
+	return r.Distance
+}
+
+// sweep returns the first click a rotation from unwound offset u covers; it covers
+// Distance consecutive clicks from there
+func sweep(r Rotation, u int) int {
+	if r.Direction == 'L' {
+		return u - r.Distance
+	}
+	return u + 1
+}
+
+// congruent counts the integers in first..first+length-1 that are ≡ residue (mod n)
+func congruent(first, length, residue, n int) int {
+	count := length / n
+	if mod(residue-first, n) < length%n {
+		count++
+	}
+	return count
+}
+
+// HitsByStart returns, for each start position, how many clicks land on the target
+func (a *Analysis) HitsByStart() []int {
+	n := a.geometry.Size
+	full := 0
+	diff := make([]int, n+1)
+	for j, r := range a.rotations {
+		full += r.Distance / n
+		rem := r.Distance % n
+		if rem == 0 {
+			continue
+		}
+		// Residues first..first+rem-1, wrapping round the end of the array
+		first := mod(sweep(r, a.offsets[j]), n)
+		end := first + rem
+		diff[first]++
+		if end <= n {
+			diff[end]--
+		} else {
+			diff[n]--
+			diff[0]++
+			diff[end-n]--
+		}
+	}
+
+	byResidue := make([]int, n)
+	running := 0
+	for residue := range byResidue {
+		running += diff[residue]
+		byResidue[residue] = full + running
+	}
+
+	hits := make([]int, n)
+	for start := range hits {
+		hits[start] = byResidue[mod(a.geometry.Target-start, n)]
+	}
+	return hits
+}
+
+// StartsWithHits returns the start positions, in increasing order, that give exactly k hits
+func (a *Analysis) StartsWithHits(k int) []int {
+	var starts []int
+	for start, hits := range a.HitsByStart() {
+		if hits == k {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+// Insertion is an extra rotation placed before rotations[Index] (Index == len means
+// after the last one) and the total hits of the resulting list
+type Insertion struct {
+	Index    int
+	Rotation Rotation
+	Hits     int
+}
+
+// BestInsertion finds the single extra rotation of at most maxDistance clicks that
+// maximises the total hits from start. Ties go to the earliest index, then to R,
+// then to the shorter rotation. ok is false if maxDistance is negative, since
+// then there is no rotation to insert.
+//
+// Inserting a rotation of signed displacement δ at index i leaves the first i
+// rotations alone and shifts every later sweep by δ, which from the start's point
+// of view is the same as moving to residue class ρ-δ. Keeping the later rotations'
+// hits for every residue (updated as i moves backwards, O(Size) per rotation) makes
+// each candidate O(1). Within one residue class of δ, every extra Size clicks adds
+// exactly one hit, so only the last Size distances up to maxDistance need trying:
+// O(m·Size) overall rather than O(m²·maxDistance) by simulation.
+func (a *Analysis) BestInsertion(start, maxDistance int) (best Insertion, ok bool) {
+	if maxDistance < 0 {
+		return Insertion{}, false
+	}
+	n := a.geometry.Size
+	m := len(a.rotations)
+	residue := mod(a.geometry.Target-start, n)
+
+	// prefix[i] is the hits of the first i rotations from start
+	prefix := make([]int, m+1)
+	for j, r := range a.rotations {
+		prefix[j+1] = prefix[j] + congruent(sweep(r, a.offsets[j]), r.Distance, residue, n)
+	}
+
+	// later[class] is the hits of rotations i..m-1 for residue class ρ, starting empty at i = m
+	later := make([]int, n)
+	best = Insertion{Index: -1, Hits: -1}
+	for i := m; i >= 0; i-- {
+		if i < m {
+			r := a.rotations[i]
+			first := sweep(r, a.offsets[i])
+			for class := range later {
+				later[class] += r.Distance / n
+			}
+			for k := 0; k < r.Distance%n; k++ {
+				later[mod(first+k, n)]++
+			}
+		}
+
+		for _, dir := range []rune{'R', 'L'} {
+			for d := max(0, maxDistance-n+1); d <= maxDistance; d++ {
+				extra := Rotation{Direction: dir, Distance: d}
+				hits := prefix[i] +
+					congruent(sweep(extra, a.offsets[i]), d, residue, n) +
+					later[mod(residue-signed(extra), n)]
+				if better(hits, i, extra, best) {
+					best = Insertion{Index: i, Rotation: extra, Hits: hits}
+				}
+			}
+		}
+	}
+	return best, true
+}
+
+// better applies BestInsertion's ordering: more hits, then earlier, then R, then shorter
+func better(hits, index int, r Rotation, than Insertion) bool {
+	if hits != than.Hits {
+		return hits > than.Hits
+	}
+	if index != than.Index {
+		return index < than.Index
+	}
+	if r.Direction != than.Rotation.Direction {
+		return r.Direction == 'R'
+	}
+	return r.Distance < than.Rotation.Distance
+}
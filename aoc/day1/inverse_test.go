@@ -0,0 +1,102 @@
+package day1
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// hitsFrom runs a Dial from start, the per-start simulation Analysis replaces
+func hitsFrom(rotations []Rotation, g Geometry, start int) int {
+	dial := NewDial(ZeroCrossingCounter{}, WithSize(g.Size), WithStart(start), WithTarget(g.Target))
+	for _, r := range rotations {
+		dial.Rotate(r)
+	}
+	return dial.Count()
+}
+
+func randomRotations(r *rand.Rand, count, maxDistance int) []Rotation {
+	rotations := make([]Rotation, count)
+	for i := range rotations {
+		rotations[i] = Rotation{Direction: rune("LR"[r.Intn(2)]), Distance: r.Intn(maxDistance + 1)}
+	}
+	return rotations
+}
+
+func TestHitsByStartMatchesDial(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for size := 1; size <= 13; size++ {
+		for trial := 0; trial < 20; trial++ {
+			g := Geometry{Size: size, Target: r.Intn(size)}
+			rotations := randomRotations(r, r.Intn(12), 3*size)
+			hits := NewAnalysis(rotations, g).HitsByStart()
+			for start := 0; start < size; start++ {
+				if want := hitsFrom(rotations, g, start); hits[start] != want {
+					t.Fatalf("%+v %v: start %d has %d hits, want %d", g, rotations, start, hits[start], want)
+				}
+			}
+		}
+	}
+}
+
+func TestStartsWithHits(t *testing.T) {
+	a := NewAnalysis(exampleRotations, Geometry{Size: 100})
+	if hits := a.HitsByStart(); hits[50] != 6 {
+		t.Fatalf("start 50 has %d hits, want the example's 6", hits[50])
+	}
+
+	for k := 0; k <= 10; k++ {
+		var want []int
+		for start := 0; start < 100; start++ {
+			if hitsFrom(exampleRotations, Geometry{Size: 100}, start) == k {
+				want = append(want, start)
+			}
+		}
+		if got := a.StartsWithHits(k); !slices.Equal(got, want) {
+			t.Errorf("k=%d: got %v, want %v", k, got, want)
+		}
+	}
+}
+
+// bestInsertionBruteForce tries every index, direction and distance and runs a Dial for each
+func bestInsertionBruteForce(rotations []Rotation, g Geometry, start, maxDistance int) Insertion {
+	best := Insertion{Index: -1, Hits: -1}
+	for i := 0; i <= len(rotations); i++ {
+		for _, dir := range []rune{'R', 'L'} {
+			for d := 0; d <= maxDistance; d++ {
+				extra := Rotation{Direction: dir, Distance: d}
+				list := slices.Insert(slices.Clone(rotations), i, extra)
+				if hits := hitsFrom(list, g, start); better(hits, i, extra, best) {
+					best = Insertion{Index: i, Rotation: extra, Hits: hits}
+				}
+			}
+		}
+	}
+	return best
+}
+
+func TestBestInsertionMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for size := 1; size <= 9; size++ {
+		for trial := 0; trial < 15; trial++ {
+			g := Geometry{Size: size, Target: r.Intn(size)}
+			rotations := randomRotations(r, r.Intn(7), 2*size)
+			start, maxDistance := r.Intn(size), r.Intn(3*size)
+
+			got, ok := NewAnalysis(rotations, g).BestInsertion(start, maxDistance)
+			if want := bestInsertionBruteForce(rotations, g, start, maxDistance); !ok || got != want {
+				t.Fatalf("%+v %v start %d max %d: got %+v, want %+v", g, rotations, start, maxDistance, got, want)
+			}
+		}
+	}
+}
+
+func TestBestInsertionRejectsNegativeDistance(t *testing.T) {
+	a := NewAnalysis([]Rotation{{Direction: 'R', Distance: 50}}, Geometry{Size: 100})
+	if got, ok := a.BestInsertion(50, -1); ok {
+		t.Errorf("max -1: got %+v, want ok = false", got)
+	}
+	if got, ok := a.BestInsertion(50, 0); !ok || got.Index != 0 || got.Hits != 1 {
+		t.Errorf("max 0: got %+v, %v; want the empty rotation at index 0 with 1 hit", got, ok)
+	}
+}
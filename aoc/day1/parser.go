@@ -15,6 +15,11 @@ type Rotation struct {
 	Distance  int
 }
 
+// String formats the rotation the way it appears in the input (e.g. "L68")
+func (r Rotation) String() string {
+	return fmt.Sprintf("%c%d", r.Direction, r.Distance)
+}
+
 // RotationParser reads and parses dial rotation instructions from input
 type RotationParser struct {
 	scanner *bufio.Scanner
@@ -91,6 +96,9 @@ func parseRotation(s string) (Rotation, error) {
 	if err != nil {
 		return Rotation{}, fmt.Errorf("invalid distance in %q: %w", s, err)
 	}
+	if distance < 0 {
+		return Rotation{}, fmt.Errorf("invalid distance in %q: must not be negative", s)
+	}
 
 	return Rotation{Direction: dir, Distance: distance}, nil
 }
@@ -0,0 +1,219 @@
+package day1
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TraceStep records what a single rotation did to the dial
+type TraceStep struct {
+	Index         int      `json:"index"`
+	Rotation      Rotation `json:"-"`
+	Before        int      `json:"before"`
+	After         int      `json:"after"`
+	Wraps         int      `json:"wraps"`         // net wraps past the top of the dial, see Geometry.Wraps
+	Contributions []int    `json:"contributions"` // one per counter, in Trace.Counters order
+}
+
+// MarshalJSON writes the rotation in its input form (e.g. "L68")
+func (s TraceStep) MarshalJSON() ([]byte, error) {
+	type plain TraceStep
+	return json.Marshal(struct {
+		Rotation string `json:"rotation"`
+		plain
+	}{s.Rotation.String(), plain(s)})
+}
+
+// Trace is the per-rotation history of a dial, filled in by Rotate
+type Trace struct {
+	Geometry Geometry    `json:"geometry"`
+	Start    int         `json:"start"`
+	Counters []string    `json:"counters"`
+	Steps    []TraceStep `json:"steps"`
+}
+
+// WithTrace makes the dial record every rotation into t
+func WithTrace(t *Trace) DialOption {
+	return func(d *Dial) { d.trace = t }
+}
+
+// begin resets the trace for a newly configured dial
+func (t *Trace) begin(d *Dial) {
+	t.Geometry = d.geometry
+	t.Start = d.position
+	t.Counters = t.Counters[:0]
+	for _, c := range d.counters {
+		t.Counters = append(t.Counters, c.Name)
+	}
+	t.Steps = t.Steps[:0]
+}
+
+// Totals returns each counter's count summed over the trace
+func (t *Trace) Totals() []int {
+	totals := make([]int, len(t.Counters))
+	for _, s := range t.Steps {
+		for i, c := range s.Contributions {
+			totals[i] += c
+		}
+	}
+	return totals
+}
+
+// counterIndex returns the position of a named counter in each step's contributions
+func (t *Trace) counterIndex(name string) (int, error) {
+	for i, n := range t.Counters {
+		if n == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no counter named %q in trace", name)
+}
+
+// WriteCSV writes one row per rotation, with a column per counter
+func (t *Trace) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := append([]string{"index", "rotation", "before", "after", "wraps"}, t.Counters...)
+	if err := cw.Write(header); err != nil {
+		return fmt.Errorf("writing header: %w", err)
+	}
+	for _, s := range t.Steps {
+		row := []string{
+			strconv.Itoa(s.Index),
+			s.Rotation.String(),
+			strconv.Itoa(s.Before),
+			strconv.Itoa(s.After),
+			strconv.Itoa(s.Wraps),
+		}
+		for _, c := range s.Contributions {
+			row = append(row, strconv.Itoa(c))
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("writing step %d: %w", s.Index, err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the whole trace as an indented JSON document
+func (t *Trace) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// StepDiff is a rotation where two counters disagree
+type StepDiff struct {
+	Index    int
+	Rotation Rotation
+	Before   int
+	A, B     int // contributions of the two counters
+}
+
+func (d StepDiff) String() string {
+	return fmt.Sprintf("#%d %v from %d: %d vs %d", d.Index, d.Rotation, d.Before, d.A, d.B)
+}
+
+// Diff compares counter nameA in trace a with counter nameB in trace b, rotation by
+// rotation, and returns the steps where their contributions differ. Both traces must
+// record the same rotations from the same start; a and b may be the same trace.
+func Diff(a *Trace, nameA string, b *Trace, nameB string) ([]StepDiff, error) {
+	ia, err := a.counterIndex(nameA)
+	if err != nil {
+		return nil, err
+	}
+	ib, err := b.counterIndex(nameB)
+	if err != nil {
+		return nil, err
+	}
+	if a.Start != b.Start || a.Geometry.Size != b.Geometry.Size || len(a.Steps) != len(b.Steps) {
+		return nil, fmt.Errorf("traces are not comparable: %d steps from %d on a %d dial vs %d steps from %d on a %d dial",
+			len(a.Steps), a.Start, a.Geometry.Size, len(b.Steps), b.Start, b.Geometry.Size)
+	}
+
+	var diffs []StepDiff
+	for i, sa := range a.Steps {
+		sb := b.Steps[i]
+		if sa.Rotation != sb.Rotation {
+			return nil, fmt.Errorf("step %d: rotations differ (%v vs %v)", sa.Index, sa.Rotation, sb.Rotation)
+		}
+		if sa.Contributions[ia] != sb.Contributions[ib] {
+			diffs = append(diffs, StepDiff{sa.Index, sa.Rotation, sa.Before, sa.Contributions[ia], sb.Contributions[ib]})
+		}
+	}
+	return diffs, nil
+}
+
+// Frame dimensions for RenderFrame: the dial is drawn as a ring of positions
+const (
+	frameRadius = 9
+	frameWidth  = 4*frameRadius + 3 // columns are doubled to look round in a terminal
+	frameHeight = 2*frameRadius + 1
+)
+
+// RenderFrame draws the dial after step i (or at the start when i is -1) as ASCII art.
+// Position 0 is at the top and positions increase clockwise, so R turns clockwise.
+// The ring shows '.' per position, 'T' for the target, '*' where the step started
+// and '@' where it ended, followed by a line describing the step.
+func (t *Trace) RenderFrame(i int) string {
+	size := t.Geometry.Size
+	before, after := t.Start, t.Start
+	if i >= 0 {
+		before, after = t.Steps[i].Before, t.Steps[i].After
+	}
+
+	grid := make([][]byte, frameHeight)
+	for y := range grid {
+		grid[y] = []byte(strings.Repeat(" ", frameWidth))
+	}
+	plot := func(position int, mark byte) {
+		angle := 2 * math.Pi * float64(position) / float64(size)
+		x := frameWidth/2 + int(math.Round(2*frameRadius*math.Sin(angle)))
+		y := frameHeight/2 - int(math.Round(frameRadius*math.Cos(angle)))
+		grid[y][x] = mark
+	}
+	for p := 0; p < size; p++ {
+		plot(p, '.')
+	}
+	plot(t.Geometry.Target, 'T')
+	plot(before, '*')
+	plot(after, '@')
+
+	label := strconv.Itoa(after)
+	copy(grid[frameHeight/2][(frameWidth-len(label))/2:], label)
+
+	var sb strings.Builder
+	for _, row := range grid {
+		sb.WriteString(strings.TrimRight(string(row), " "))
+		sb.WriteByte('\n')
+	}
+
+	if i < 0 {
+		fmt.Fprintf(&sb, "start at %d (size %d, target %d)\n", t.Start, size, t.Geometry.Target)
+		return sb.String()
+	}
+	s := t.Steps[i]
+	fmt.Fprintf(&sb, "step %d/%d: %v  %d -> %d  wraps %+d", i+1, len(t.Steps), s.Rotation, s.Before, s.After, s.Wraps)
+	for j, name := range t.Counters {
+		fmt.Fprintf(&sb, "  %s %+d", name, s.Contributions[j])
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// Animate writes every frame to w, clearing the terminal between frames
+func (t *Trace) Animate(w io.Writer, delay time.Duration) error {
+	for i := -1; i < len(t.Steps); i++ {
+		if _, err := fmt.Fprint(w, "\033[H\033[2J"+t.RenderFrame(i)); err != nil {
+			return err
+		}
+		time.Sleep(delay)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package day1
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var exampleRotations = []Rotation{
+	{'L', 68}, {'L', 30}, {'R', 48}, {'L', 5}, {'R', 60},
+	{'L', 55}, {'L', 1}, {'L', 99}, {'R', 14}, {'L', 82},
+}
+
+// offByOneCounter forgets hits on the final click, a typical crossing bug
+type offByOneCounter struct{}
+
+func (offByOneCounter) Count(r Rotation, position int, g Geometry) int {
+	return g.Hits(Rotation{r.Direction, max(r.Distance-1, 0)}, position)
+}
+
+func traceExample(counters ...NamedCounter) *Trace {
+	var trace Trace
+	dial := NewMultiDial(counters, WithTrace(&trace))
+	for _, r := range exampleRotations {
+		dial.Rotate(r)
+	}
+	return &trace
+}
+
+func TestTraceCSV(t *testing.T) {
+	trace := traceExample(NamedCounter{"part1", EndPositionCounter{}}, NamedCounter{"part2", ZeroCrossingCounter{}})
+
+	var buf bytes.Buffer
+	if err := trace.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(exampleRotations)+1 {
+		t.Fatalf("got %d CSV lines, want %d", len(lines), len(exampleRotations)+1)
+	}
+	if want := "index,rotation,before,after,wraps,part1,part2"; lines[0] != want {
+		t.Errorf("header = %q, want %q", lines[0], want)
+	}
+	if want := "0,L68,50,82,-1,0,1"; lines[1] != want {
+		t.Errorf("first row = %q, want %q", lines[1], want)
+	}
+	if totals := trace.Totals(); totals[0] != 3 || totals[1] != 6 {
+		t.Errorf("totals = %v, want [3 6]", totals)
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	trace := traceExample(NamedCounter{"part2", ZeroCrossingCounter{}})
+
+	var buf bytes.Buffer
+	if err := trace.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Start int `json:"start"`
+		Steps []struct {
+			Rotation      string `json:"rotation"`
+			After         int    `json:"after"`
+			Contributions []int  `json:"contributions"`
+		} `json:"steps"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decoding %s: %v", buf.String(), err)
+	}
+	if decoded.Start != 50 || len(decoded.Steps) != len(exampleRotations) {
+		t.Fatalf("decoded start %d with %d steps", decoded.Start, len(decoded.Steps))
+	}
+	if s := decoded.Steps[2]; s.Rotation != "R48" || s.After != 0 || s.Contributions[0] != 1 {
+		t.Errorf("step 2 = %+v", s)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	trace := traceExample(NamedCounter{"fast", ZeroCrossingCounter{}}, NamedCounter{"stepped", StepCounter{}})
+	if diffs, err := Diff(trace, "fast", trace, "stepped"); err != nil || len(diffs) != 0 {
+		t.Fatalf("fast vs stepped: %v, %v", diffs, err)
+	}
+
+	buggy := traceExample(NamedCounter{"buggy", offByOneCounter{}})
+	diffs, err := Diff(trace, "fast", buggy, "buggy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The rotations that end exactly on 0 are the ones the bug misses
+	var indices []int
+	for _, d := range diffs {
+		indices = append(indices, d.Index)
+	}
+	if got, want := indices, []int{2, 5, 7}; !slices.Equal(got, want) {
+		t.Errorf("differing steps = %v, want %v", got, want)
+	}
+
+	if _, err := Diff(trace, "missing", buggy, "buggy"); err == nil {
+		t.Error("expected an error for an unknown counter")
+	}
+}
+
+func TestRenderFrame(t *testing.T) {
+	trace := traceExample(NamedCounter{"part2", ZeroCrossingCounter{}})
+
+	frame := trace.RenderFrame(0)
+	for _, want := range []string{"T", "*", "@", "82", "step 1/10: L68  50 -> 82  wraps -1  part2 +1"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame for step 0 is missing %q:\n%s", want, frame)
+		}
+	}
+	if start := trace.RenderFrame(-1); !strings.Contains(start, "start at 50") {
+		t.Errorf("start frame:\n%s", start)
+	}
+}
@@ -0,0 +1,161 @@
+package day10
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"adv2025/aoc/difftest"
+)
+
+// bruteForceMinPresses tries every subset of buttons and returns the smallest
+// one that produces the target lights, or -1.
+func bruteForceMinPresses(m *Machine) int {
+	best := -1
+	for mask := 0; mask < 1<<len(m.Buttons); mask++ {
+		lights := make([]bool, len(m.TargetLights))
+		presses := 0
+		for b, button := range m.Buttons {
+			if mask>>b&1 == 0 {
+				continue
+			}
+			presses++
+			for _, light := range button {
+				if light < len(lights) {
+					lights[light] = !lights[light]
+				}
+			}
+		}
+		matches := true
+		for i := range lights {
+			if lights[i] != m.TargetLights[i] {
+				matches = false
+				break
+			}
+		}
+		if matches && (best < 0 || presses < best) {
+			best = presses
+		}
+	}
+	return best
+}
+
+// bruteForceMinJoltage searches every press count for every button, bounded
+// by the remaining target of the counters each button feeds.
+func bruteForceMinJoltage(m *Machine) int {
+	remaining := append([]int{}, m.Joltages...)
+	best := -1
+
+	var search func(button, presses int)
+	search = func(button, presses int) {
+		if best >= 0 && presses >= best {
+			return
+		}
+		if button == len(m.Buttons) {
+			for _, r := range remaining {
+				if r != 0 {
+					return
+				}
+			}
+			best = presses
+			return
+		}
+
+		limit := -1
+		for _, c := range m.Buttons[button] {
+			if c < len(remaining) && (limit < 0 || remaining[c] < limit) {
+				limit = remaining[c]
+			}
+		}
+		if limit < 0 {
+			limit = 0 // button feeds no counter: pressing it never helps
+		}
+
+		for n := 0; n <= limit; n++ {
+			for _, c := range m.Buttons[button] {
+				if c < len(remaining) {
+					remaining[c] -= n
+				}
+			}
+			search(button+1, presses+n)
+			for _, c := range m.Buttons[button] {
+				if c < len(remaining) {
+					remaining[c] += n
+				}
+			}
+		}
+	}
+	search(0, 0)
+	return best
+}
+
+// randomMachine builds a machine whose joltage targets are reachable, so the
+// joltage property exercises the search rather than the "no solution" path.
+func randomMachine(r *rand.Rand) *Machine {
+	n := 1 + r.Intn(5)
+	m := &Machine{TargetLights: make([]bool, n), Joltages: make([]int, n)}
+	for i := range m.TargetLights {
+		m.TargetLights[i] = r.Intn(2) == 1
+	}
+	for b := 1 + r.Intn(5); b > 0; b-- {
+		var button []int
+		for light := 0; light < n; light++ {
+			if r.Intn(2) == 1 {
+				button = append(button, light)
+			}
+		}
+		if len(button) == 0 {
+			button = []int{r.Intn(n)}
+		}
+		m.Buttons = append(m.Buttons, button)
+		presses := r.Intn(4)
+		for _, light := range button {
+			m.Joltages[light] += presses
+		}
+	}
+	return m
+}
+
+func shrinkMachine(m *Machine) []*Machine {
+	var out []*Machine
+	for _, buttons := range difftest.ShrinkSlice(m.Buttons, nil) {
+		if len(buttons) > 0 {
+			out = append(out, &Machine{TargetLights: m.TargetLights, Buttons: buttons, Joltages: m.Joltages})
+		}
+	}
+	for i := range m.TargetLights {
+		if m.TargetLights[i] {
+			lights := append([]bool{}, m.TargetLights...)
+			lights[i] = false
+			out = append(out, &Machine{TargetLights: lights, Buttons: m.Buttons, Joltages: m.Joltages})
+		}
+	}
+	for i := range m.Joltages {
+		for _, j := range difftest.ShrinkInt(m.Joltages[i], 0) {
+			joltages := append([]int{}, m.Joltages...)
+			joltages[i] = j
+			out = append(out, &Machine{TargetLights: m.TargetLights, Buttons: m.Buttons, Joltages: joltages})
+		}
+	}
+	return out
+}
+
+func machineProperty(name string, reference, candidate func(*Machine) int) difftest.Property[*Machine] {
+	return difftest.Property[*Machine]{
+		Name:      name,
+		Reference: func(m *Machine) (int, error) { return reference(m), nil },
+		Candidate: func(m *Machine) (int, error) { return candidate(m), nil },
+		Generate:  randomMachine,
+		Shrink:    shrinkMachine,
+		Encode:    func(m *Machine) string { return m.Format() + "\n" },
+		Decode:    func(s string) (*Machine, error) { return ParseMachine(strings.TrimSpace(s)) },
+	}
+}
+
+func TestSolveMinPressesMatchesBruteForce(t *testing.T) {
+	difftest.Check(t, machineProperty("min-presses", bruteForceMinPresses, SolveMinPresses))
+}
+
+func TestSolveMinJoltageMatchesBruteForce(t *testing.T) {
+	difftest.Check(t, machineProperty("min-joltage", bruteForceMinJoltage, SolveMinJoltage))
+}
@@ -0,0 +1,237 @@
+package day10
+
+import (
+	"math"
+	"math/big"
+)
+
+// solveJoltageExact returns the true minimum number of presses for A·x = b,
+// x ≥ 0 and integer, or -1 if there is none. upper is a press count already
+// known to work (-1 if none); it only speeds the search up. ok is false if the
+// eliminated system's coefficients do not fit in an int, and the search was
+// not run.
+//
+// Algorithm: exact elimination, then branch and bound over the free variables.
+//
+// Gauss-Jordan elimination over the rationals (big.Rat, so nothing is lost to
+// rounding) writes every pivot variable in terms of the free ones:
+//
+//	d_r · x_pivot(r) = e_r − Σ g_rf · x_f      (integers, d_r > 0)
+//
+// Every solution of the system is one choice of free values, so searching them
+// all is exact. Each button can be pressed at most as often as the smallest
+// target among its counters, less the presses of the free buttons already
+// chosen that feed them, which bounds the search. Two bounds prune it:
+// - Feasibility: if even the most favourable remaining values leave some pivot negative
+// - Cost: the total is linear in the free values, so its lowest possible value is known
+//
+// Values are tried cheapest first, so a branch stops as soon as its cost
+// bound reaches the best total found. The work still grows exponentially with
+// the number of free variables (buttons beyond the rank of the matrix); puzzle
+// machines have few, and take milliseconds.
+//
+// The matrix is 0/1 and small, so the d_r, e_r and g_rf stay tiny (they are
+// minors of the matrix) and int arithmetic suffices after elimination; they
+// are still checked as they are converted.
+func solveJoltageExact(matrix [][]int, targets []int, numButtons, numCounters, upper int) (presses int, ok bool) {
+	// Reduced row echelon form of [A|b]
+	aug := make([][]*big.Rat, numCounters)
+	for i := range aug {
+		aug[i] = make([]*big.Rat, numButtons+1)
+		for j := 0; j < numButtons; j++ {
+			aug[i][j] = big.NewRat(int64(matrix[i][j]), 1)
+		}
+		aug[i][numButtons] = big.NewRat(int64(targets[i]), 1)
+	}
+
+	var pivotCols []int
+	isPivot := make([]bool, numButtons)
+	row := 0
+	for col := 0; col < numButtons && row < numCounters; col++ {
+		pivot := -1
+		for r := row; r < numCounters; r++ {
+			if aug[r][col].Sign() != 0 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		aug[row], aug[pivot] = aug[pivot], aug[row]
+
+		inv := new(big.Rat).Inv(aug[row][col])
+		for j := range aug[row] {
+			aug[row][j].Mul(aug[row][j], inv)
+		}
+		for r := range aug {
+			if r == row || aug[r][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Set(aug[r][col])
+			for j := range aug[r] {
+				aug[r][j].Sub(aug[r][j], new(big.Rat).Mul(factor, aug[row][j]))
+			}
+		}
+		pivotCols = append(pivotCols, col)
+		isPivot[col] = true
+		row++
+	}
+
+	// A zero row with a non-zero target means the counters contradict each other
+	for r := row; r < numCounters; r++ {
+		if aug[r][numButtons].Sign() != 0 {
+			return -1, true
+		}
+	}
+
+	var free []int
+	for j := 0; j < numButtons; j++ {
+		if !isPivot[j] {
+			free = append(free, j)
+		}
+	}
+
+	// Integer form of each pivot row: d·x_pivot = e − Σ g[f]·x_free[f]
+	type pivotRow struct {
+		d, e int
+		g    []int
+	}
+	rows := make([]pivotRow, len(pivotCols))
+	for r := range rows {
+		lcm := big.NewInt(1)
+		scale := func(q *big.Rat) {
+			gcd := new(big.Int).GCD(nil, nil, lcm, q.Denom())
+			lcm.Mul(lcm, new(big.Int).Quo(q.Denom(), gcd))
+		}
+		scale(aug[r][numButtons])
+		for _, f := range free {
+			scale(aug[r][f])
+		}
+		fits := lcm.IsInt64()
+		asInt := func(q *big.Rat) int {
+			n := new(big.Int).Mul(q.Num(), new(big.Int).Quo(lcm, q.Denom()))
+			fits = fits && n.IsInt64()
+			return int(n.Int64())
+		}
+		rows[r] = pivotRow{d: int(lcm.Int64()), e: asInt(aug[r][numButtons]), g: make([]int, len(free))}
+		for i, f := range free {
+			rows[r].g[i] = asInt(aug[r][f])
+		}
+		if !fits {
+			return 0, false
+		}
+	}
+
+	// Each free button is capped by what the counters it feeds still lack
+	left := append([]int(nil), targets...) // targets less the free presses chosen so far
+	capOf := func(i int) int {
+		most := -1
+		for c := 0; c < numCounters; c++ {
+			if matrix[c][free[i]] != 0 && (most < 0 || left[c] < most) {
+				most = left[c]
+			}
+		}
+		return max(most, 0) // a button feeding no counter is never worth pressing
+	}
+
+	// total = base + Σ weight[i]·x_free[i]. floor[i] is the least the free
+	// variables from i on can add to it under the caps set by the full
+	// targets, which holds wherever the search is
+	base := 0.0
+	weight := make([]float64, len(free))
+	for i := range weight {
+		weight[i] = 1
+	}
+	for _, pr := range rows {
+		base += float64(pr.e) / float64(pr.d)
+		for i, g := range pr.g {
+			weight[i] -= float64(g) / float64(pr.d)
+		}
+	}
+	floor := make([]float64, len(free)+1)
+	for i := len(free) - 1; i >= 0; i-- {
+		floor[i] = floor[i+1] + min(0, weight[i]*float64(capOf(i)))
+	}
+
+	best := upper
+	if best < 0 {
+		best = math.MaxInt
+	}
+	// Totals are integers: a bound not below best-1 cannot improve on best
+	hopeless := func(bound float64) bool {
+		return best != math.MaxInt && bound > float64(best)-1+1e-6
+	}
+	numerator := make([]int, len(rows)) // e − Σ g·x over the free values chosen so far
+	for r, pr := range rows {
+		numerator[r] = pr.e
+	}
+	caps := make([]int, len(free))
+	reach := make([]int, len(rows))
+
+	var search func(i, freeSum int, cost float64)
+	search = func(i, freeSum int, cost float64) {
+		// Bounds from the caps as they stand now, tighter than floor
+		lowest := 0.0
+		clear(reach)
+		for j := i; j < len(free); j++ {
+			caps[j] = capOf(j)
+			lowest += min(0, weight[j]*float64(caps[j]))
+			for r, pr := range rows {
+				reach[r] += max(0, -pr.g[j]) * caps[j]
+			}
+		}
+		if hopeless(cost + lowest) {
+			return
+		}
+		for r := range rows {
+			if numerator[r]+reach[r] < 0 {
+				return
+			}
+		}
+
+		if i == len(free) {
+			total := freeSum
+			for r, pr := range rows {
+				if numerator[r]%pr.d != 0 {
+					return
+				}
+				total += numerator[r] / pr.d
+			}
+			best = min(best, total)
+			return
+		}
+
+		// Cheapest values first, so the search can stop once even the best
+		// case for the rest costs too much
+		most, x, step := caps[i], 0, 1
+		if weight[i] < 0 {
+			x, step = most, -1
+		}
+		for ; x >= 0 && x <= most; x += step {
+			spent := cost + weight[i]*float64(x)
+			if hopeless(spent + floor[i+1]) {
+				break
+			}
+			for r, pr := range rows {
+				numerator[r] -= pr.g[i] * x
+			}
+			for c := 0; c < numCounters; c++ {
+				left[c] -= matrix[c][free[i]] * x
+			}
+			search(i+1, freeSum+x, spent)
+			for r, pr := range rows {
+				numerator[r] += pr.g[i] * x
+			}
+			for c := 0; c < numCounters; c++ {
+				left[c] += matrix[c][free[i]] * x
+			}
+		}
+	}
+	search(0, 0, base)
+
+	if best == math.MaxInt {
+		return -1, true
+	}
+	return best, true
+}
@@ -0,0 +1,34 @@
+package day10
+
+import (
+	"reflect"
+	"testing"
+)
+
+func FuzzParseMachine(f *testing.F) {
+	for _, seed := range []string{
+		"[.##.] (3) (1,3) (2) (2,3) (0,2) (0,1) {3,5,4,7}",
+		"[...#.] (0,2,3,4) (2,3) (0,4) (0,1,2) (1,2,3,4) {7,5,12,7,2}",
+		"[.###.#] (0,1,2,3,4) (0,3,4) (0,1,2,4,5) (1,2) {10,11,11,5,10,5}",
+		"[#]",
+		"[.] (0,,1) {}",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		m, err := ParseMachine(line)
+		if err != nil {
+			return
+		}
+
+		formatted := m.Format()
+		again, err := ParseMachine(formatted)
+		if err != nil {
+			t.Fatalf("reparsing %q (from %q): %v", formatted, line, err)
+		}
+		if !reflect.DeepEqual(again, m) {
+			t.Fatalf("round trip of %q: got %+v, want %+v", line, again, m)
+		}
+	})
+}
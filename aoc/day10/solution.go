@@ -254,230 +254,24 @@ func solveMinJoltageInteger(buttons [][]int, targets []int, numButtons, numCount
 		}
 	}
 
-	// Day 9 lesson: Try simple approaches first before complex algorithms
-	// For this problem, we can use a greedy backtracking search
-
-	// First try: simple greedy approach (works for many cases)
-	greedy := solveGreedySimple(matrix, targets, numButtons, numCounters)
-	if greedy >= 0 {
-		return greedy
-	}
-
-	// If greedy fails, try Gaussian elimination
-	gaussian := solveJoltageGreedy(matrix, targets, numButtons, numCounters)
-	if gaussian >= 0 {
-		return gaussian
-	}
-
-	// Last resort: use floating point Gaussian elimination to find solution structure
-	// This avoids integer coefficient explosion
-	return solveWithFloatGaussian(matrix, targets, numButtons, numCounters)
-}
-
-// solveWithFloatGaussian uses floating point arithmetic to solve the system,
-// avoiding the integer coefficient explosion problem.
-func solveWithFloatGaussian(matrix [][]int, targets []int, numButtons, numCounters int) int {
-	// Convert to float64 for Gaussian elimination
-	aug := make([][]float64, numCounters)
-	for i := range aug {
-		aug[i] = make([]float64, numButtons+1)
-		for j := 0; j < numButtons; j++ {
-			aug[i][j] = float64(matrix[i][j])
-		}
-		aug[i][numButtons] = float64(targets[i])
-	}
-
-	// Gaussian elimination with partial pivoting to RREF
-	pivotCols := make([]int, 0)
-	currentRow := 0
-
-	for col := 0; col < numButtons && currentRow < numCounters; col++ {
-		// Find best pivot (largest absolute value) in this column
-		maxRow := -1
-		maxVal := 0.0
-		for row := currentRow; row < numCounters; row++ {
-			if abs64(aug[row][col]) > maxVal {
-				maxVal = abs64(aug[row][col])
-				maxRow = row
-			}
-		}
-
-		// Skip if no good pivot found
-		if maxVal < 1e-10 {
-			continue
-		}
-
-		// Swap rows
-		aug[currentRow], aug[maxRow] = aug[maxRow], aug[currentRow]
-		pivotCols = append(pivotCols, col)
-
-		// Scale pivot row to make pivot = 1
-		pivot := aug[currentRow][col]
-		for c := 0; c <= numButtons; c++ {
-			aug[currentRow][c] /= pivot
-		}
-
-		// Eliminate all other rows (forward and backward)
-		for row := 0; row < numCounters; row++ {
-			if row == currentRow {
-				continue
-			}
-			if abs64(aug[row][col]) < 1e-10 {
-				continue
-			}
-			factor := aug[row][col]
-			for c := 0; c <= numButtons; c++ {
-				aug[row][c] -= factor * aug[currentRow][c]
-			}
-		}
-
-		currentRow++
-	}
-
-	// Now we're in RREF. Identify free variables
-	freeVarList := make([]int, 0)
-	for i := 0; i < numButtons; i++ {
-		isFree := true
-		for _, pivotCol := range pivotCols {
-			if pivotCol == i {
-				isFree = false
-				break
-			}
-		}
-		if isFree {
-			freeVarList = append(freeVarList, i)
-		}
-	}
-
-
-	// Enumerate free variable assignments to find minimum
-	// Find maximum target to bound search
-	maxTarget := 0
-	for _, t := range targets {
-		if t > maxTarget {
-			maxTarget = t
-		}
-	}
-
-	// Limit free variable search based on count
-	if len(freeVarList) > 8 {
-		// Too many free variables - use greedy with free vars = 0
-		return solveWithFreeVarsZero(aug, pivotCols, freeVarList, matrix, targets, numButtons, numCounters)
-	}
-
-	minTotal := -1
-	// Need to allow larger free variable values to avoid negative pivot variables
-	maxFreeVal := maxTarget // Try up to maxTarget
-
-	var enumerate func(int, []float64)
-	enumerate = func(idx int, currentSol []float64) {
-		if idx == len(freeVarList) {
-			// Compute pivot variables from RREF
-			sol := make([]float64, numButtons)
-			copy(sol, currentSol)
-
-			// Read pivot values from RREF (accounting for free variables)
-			for rowIdx, pivotCol := range pivotCols {
-				val := aug[rowIdx][numButtons]
-				// Subtract contributions from free variables
-				for freeVar := range freeVarList {
-					freeCol := freeVarList[freeVar]
-					val -= aug[rowIdx][freeCol] * sol[freeCol]
-				}
-				sol[pivotCol] = val
-			}
-
-			// Round and verify
-			intSol := make([]int, numButtons)
-			for i := range sol {
-				rounded := int(sol[i] + 0.5)
-				if rounded < 0 {
-					return // Invalid - negative button presses not allowed
-				}
-				intSol[i] = rounded
-			}
-
-			// Verify against original constraints
-			valid := true
-			for row := 0; row < numCounters; row++ {
-				sum := 0
-				for col := 0; col < numButtons; col++ {
-					sum += matrix[row][col] * intSol[col]
-				}
-				if sum != targets[row] {
-					valid = false
-					break
-				}
-			}
-
-			if valid {
-				total := 0
-				for _, presses := range intSol {
-					total += presses
-				}
-				if minTotal < 0 || total < minTotal {
-					minTotal = total
-				}
-			}
-			return
-		}
-
-		// Try values for this free variable
-		freeVar := freeVarList[idx]
-		for val := 0; val <= maxFreeVal; val++ {
-			newSol := make([]float64, numButtons)
-			copy(newSol, currentSol)
-			newSol[freeVar] = float64(val)
-			enumerate(idx+1, newSol)
-		}
-	}
-
-	enumerate(0, make([]float64, numButtons))
-	return minTotal
-}
-
-func solveWithFreeVarsZero(aug [][]float64, pivotCols, freeVarList []int, matrix [][]int, targets []int, numButtons, numCounters int) int {
-	// Simple case: set all free variables to 0
-	solution := make([]float64, numButtons)
-	for rowIdx, col := range pivotCols {
-		if rowIdx < len(aug) {
-			solution[col] = aug[rowIdx][numButtons]
-		}
-	}
-
-	// Round to integers
-	intSolution := make([]int, numButtons)
-	for i := range solution {
-		rounded := int(solution[i] + 0.5)
-		if rounded < 0 {
-			return -1
-		}
-		intSolution[i] = rounded
-	}
-
-	// Verify
-	for row := 0; row < numCounters; row++ {
-		sum := 0
-		for col := 0; col < numButtons; col++ {
-			sum += matrix[row][col] * intSolution[col]
-		}
-		if sum != targets[row] {
-			return -1
-		}
-	}
-
-	total := 0
-	for _, presses := range intSolution {
-		total += presses
-	}
-	return total
-}
-
-func abs64(x float64) float64 {
-	if x < 0 {
-		return -x
-	}
-	return x
+	// The heuristics below are fast and verify their answers against the
+	// targets, so each result is a valid press count, but none is guaranteed
+	// minimal (the difftest harness finds machines where greedy overshoots).
+	// Their best result only seeds the exact search with an upper bound.
+	best := -1
+	for _, strategy := range []func([][]int, []int, int, int) int{
+		solveGreedySimple,
+		solveJoltageGreedy,
+	} {
+		presses := strategy(matrix, targets, numButtons, numCounters)
+		if presses >= 0 && (best < 0 || presses < best) {
+			best = presses
+		}
+	}
+	if presses, ok := solveJoltageExact(matrix, targets, numButtons, numCounters, best); ok {
+		return presses
+	}
+	return best // too large to eliminate exactly; valid, though perhaps not minimal
 }
 
 // tryRoundingStrategies tries different rounding approaches when direct rounding fails.
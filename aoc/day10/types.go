@@ -88,3 +88,39 @@ func (m *Machine) String() string {
 	}
 	return fmt.Sprintf("[%s] %d buttons", string(lights), len(m.Buttons))
 }
+
+// Format writes the machine back in the input line format accepted by ParseMachine.
+// The joltage block is omitted when the machine has no joltage targets.
+func (m *Machine) Format() string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for _, on := range m.TargetLights {
+		if on {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	sb.WriteByte(']')
+
+	for _, button := range m.Buttons {
+		sb.WriteString(" (")
+		sb.WriteString(joinInts(button))
+		sb.WriteByte(')')
+	}
+
+	if len(m.Joltages) > 0 {
+		sb.WriteString(" {")
+		sb.WriteString(joinInts(m.Joltages))
+		sb.WriteByte('}')
+	}
+	return sb.String()
+}
+
+func joinInts(nums []int) string {
+	strs := make([]string, len(nums))
+	for i, n := range nums {
+		strs[i] = strconv.Itoa(n)
+	}
+	return strings.Join(strs, ",")
+}
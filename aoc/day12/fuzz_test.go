@@ -0,0 +1,117 @@
+package day12
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const example = `0:
+###
+##.
+##.
+
+1:
+###
+##.
+.##
+
+2:
+.##
+###
+##.
+
+3:
+##.
+###
+##.
+
+4:
+###
+#..
+###
+
+5:
+###
+.#.
+###
+
+4x4: 0 0 0 0 2 0
+12x5: 1 0 1 0 2 2
+12x5: 1 0 1 0 3 2
+`
+
+// A shape's grid can end at the next header or region line rather than at a
+// blank line; that line still has to be read
+func TestParseWithoutBlankLines(t *testing.T) {
+	for input, want := range map[string][2]int{
+		"0:\n#\n4x4: 1\n":           {1, 1},
+		"0:\n#\n1:\n#\n":            {2, 0},
+		"0:\n#\n1:\n##\n2x2: 0 1\n": {2, 1},
+	} {
+		shapes, regions, err := NewParser(strings.NewReader(input)).ParseAll()
+		if err != nil {
+			t.Errorf("%q: %v", input, err)
+			continue
+		}
+		if len(shapes) != want[0] || len(regions) != want[1] {
+			t.Errorf("%q: %d shapes and %d regions, want %d and %d", input, len(shapes), len(regions), want[0], want[1])
+		}
+	}
+}
+
+// maxFuzzInput is the longest input FuzzParseAll tries, well above the seeds
+const maxFuzzInput = 512
+
+func FuzzParseAll(f *testing.F) {
+	for _, seed := range []string{example, "0:\n\n4x4:\n", "7:\n..#\n\n", "1x1: 0", "0:\n#\n4x4: 1\n", "0:\n#\n1:\n#\n"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		// The fuzzer minimises every new input that finds new coverage, in time
+		// quadratic in its length; inputs many kilobytes long stall it for a
+		// minute each without reaching anything a short one cannot
+		if len(input) > maxFuzzInput {
+			return
+		}
+		shapes, regions, err := NewParser(strings.NewReader(input)).ParseAll()
+		if err != nil {
+			return
+		}
+
+		formatted := FormatInput(shapes, regions)
+		againShapes, againRegions, err := NewParser(strings.NewReader(formatted)).ParseAll()
+		if err != nil {
+			t.Fatalf("reparsing %q (from %q): %v", formatted, input, err)
+		}
+		if !reflect.DeepEqual(againShapes, shapes) {
+			t.Fatalf("shape round trip of %q: got %+v, want %+v", input, againShapes, shapes)
+		}
+		if !reflect.DeepEqual(againRegions, regions) {
+			t.Fatalf("region round trip of %q: got %+v, want %+v", input, againRegions, regions)
+		}
+	})
+}
+
+func FuzzParseRegion(f *testing.F) {
+	for _, seed := range []string{"4x4: 0 0 0 0 2 0", "12x5: 1 0 1 0 2 2", "1x1:", "x:", "-1x2: 3"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		var p Parser
+		region, err := p.parseRegion(line)
+		if err != nil {
+			return
+		}
+
+		again, err := p.parseRegion(region.String())
+		if err != nil {
+			t.Fatalf("reparsing %q (from %q): %v", region.String(), line, err)
+		}
+		if !reflect.DeepEqual(again, region) {
+			t.Fatalf("round trip of %q: got %+v, want %+v", line, again, region)
+		}
+	})
+}
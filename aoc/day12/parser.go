@@ -17,6 +17,7 @@ import (
 //   parser := NewParser(strings.NewReader("test input"))
 type Parser struct {
 	scanner *bufio.Scanner
+	peeked  *string // a line read ahead and put back by unread
 }
 
 // NewParser creates a parser from an io.Reader.
@@ -29,28 +30,50 @@ func NewParser(r io.Reader) *Parser {
 	}
 }
 
+// next returns the next input line, or false at the end of the input.
+func (p *Parser) next() (string, bool) {
+	if p.peeked != nil {
+		line := *p.peeked
+		p.peeked = nil
+		return line, true
+	}
+	if !p.scanner.Scan() {
+		return "", false
+	}
+	return p.scanner.Text(), true
+}
+
+// unread puts a line back, so that the next call to next returns it again.
+// bufio.Scanner cannot step back, so the parser keeps one line of lookahead.
+func (p *Parser) unread(line string) {
+	p.peeked = &line
+}
+
 // ParseAll reads all shapes and regions from the input.
 func (p *Parser) ParseAll() ([]Shape, []Region, error) {
 	var shapes []Shape
 	var regions []Region
 
-	for p.scanner.Scan() {
-		line := p.scanner.Text()
+	for {
+		line, ok := p.next()
+		if !ok {
+			break
+		}
 
-		// Check if this is a shape definition (ends with :)
-		if strings.HasSuffix(strings.TrimSpace(line), ":") {
-			shape, err := p.parseShape(line)
-			if err != nil {
-				return nil, nil, fmt.Errorf("parsing shape: %w", err)
-			}
-			shapes = append(shapes, shape)
-		} else if strings.Contains(line, "x") && strings.Contains(line, ":") {
-			// This is a region definition
+		// Check regions first: a region with no presents ("4x4:") also ends with ':'
+		if strings.Contains(line, "x") && strings.Contains(line, ":") {
 			region, err := p.parseRegion(line)
 			if err != nil {
 				return nil, nil, fmt.Errorf("parsing region: %w", err)
 			}
 			regions = append(regions, region)
+		} else if strings.HasSuffix(strings.TrimSpace(line), ":") {
+			// This is a shape definition (ends with :)
+			shape, err := p.parseShape(line)
+			if err != nil {
+				return nil, nil, fmt.Errorf("parsing shape: %w", err)
+			}
+			shapes = append(shapes, shape)
 		}
 		// Skip empty lines
 	}
@@ -73,8 +96,11 @@ func (p *Parser) parseShape(headerLine string) (Shape, error) {
 
 	// Read the grid lines for this shape
 	var gridLines []string
-	for p.scanner.Scan() {
-		line := p.scanner.Text()
+	for {
+		line, ok := p.next()
+		if !ok {
+			break
+		}
 		trimmed := strings.TrimSpace(line)
 
 		// Empty line marks end of shape
@@ -82,12 +108,10 @@ func (p *Parser) parseShape(headerLine string) (Shape, error) {
 			break
 		}
 
-		// If we hit another shape or region definition, we're done
+		// If we hit another shape or region definition, we're done; put the
+		// line back for ParseAll to read
 		if strings.HasSuffix(trimmed, ":") || (strings.Contains(trimmed, "x") && strings.Contains(trimmed, ":")) {
-			// Put the line back by scanning it again (unfortunately we can't unscan)
-			// We'll handle this by breaking and letting the outer loop process it
-			// Actually, bufio.Scanner doesn't support putting back, so we need a different approach
-			// For now, let's just break and assume proper formatting
+			p.unread(line)
 			break
 		}
 
@@ -131,6 +155,10 @@ func (p *Parser) parseRegion(line string) (Region, error) {
 		return Region{}, fmt.Errorf("invalid height: %w", err)
 	}
 
+	if width < 0 || height < 0 {
+		return Region{}, fmt.Errorf("invalid dimensions: %q", parts[0])
+	}
+
 	// Parse present counts
 	countStrs := strings.Fields(parts[1])
 	presents := make([]int, len(countStrs))
@@ -139,6 +167,9 @@ func (p *Parser) parseRegion(line string) (Region, error) {
 		if err != nil {
 			return Region{}, fmt.Errorf("invalid present count: %w", err)
 		}
+		if count < 0 {
+			return Region{}, fmt.Errorf("invalid present count: %d", count)
+		}
 		presents[i] = count
 	}
 
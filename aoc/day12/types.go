@@ -25,6 +25,16 @@ type Region struct {
 	Presents []int // presents[i] = count of shape i needed
 }
 
+// String formats the region as an input line (e.g. "12x5: 1 0 1 0 2 2").
+func (r Region) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%dx%d:", r.Width, r.Height)
+	for _, count := range r.Presents {
+		fmt.Fprintf(&sb, " %d", count)
+	}
+	return sb.String()
+}
+
 // Grid represents a placement grid for checking if presents fit.
 type Grid struct {
 	Width   int
@@ -173,3 +183,45 @@ func (s *Shape) AllTransformations() []Shape {
 	}
 	return result
 }
+
+// Format renders the shape in the input format: an "N:" header followed by
+// its grid rows and a terminating blank line.
+func (s *Shape) Format() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d:\n", s.ID)
+
+	width, height := 0, 0
+	filled := make(map[Point]bool, len(s.Points))
+	for _, p := range s.Points {
+		filled[p] = true
+		width = max(width, p.X+1)
+		height = max(height, p.Y+1)
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if filled[Point{X: x, Y: y}] {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// FormatInput renders shapes and regions as a complete puzzle input,
+// the inverse of Parser.ParseAll.
+func FormatInput(shapes []Shape, regions []Region) string {
+	var sb strings.Builder
+	for i := range shapes {
+		sb.WriteString(shapes[i].Format())
+	}
+	for _, r := range regions {
+		sb.WriteString(r.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,116 @@
+package day2
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"adv2025/aoc/difftest"
+)
+
+const example = "11-22,95-115,998-1012,1188511880-1188511890,222220-222224,1698522-1698528,446443-446449,38593856-38593862,565653-565659,824824821-824824827,2121212118-2121212124"
+
+func sumRanges(ranges []Range, sum func(Range) int) int {
+	total := 0
+	for _, r := range ranges {
+		total += sum(r)
+	}
+	return total
+}
+
+// sumInvalid adds up every ID in the ranges that v reports invalid, checking them
+// one at a time. It is the reference the enumeration in enumerate.go is tested against.
+func sumInvalid(ranges []Range, v Validator) int {
+	sum := 0
+	for _, r := range ranges {
+		for id := r.Start; id <= r.End; id++ {
+			if v.IsInvalid(id) {
+				sum += id
+			}
+		}
+	}
+	return sum
+}
+
+// randomRange picks a short range somewhere below 10^digits, so that both
+// the reference and the enumeration see IDs of many lengths
+func randomRange(r *rand.Rand) Range {
+	digits := 1 + r.Intn(12)
+	start := r.Intn(pow10[digits])
+	return Range{Start: start, End: start + r.Intn(5000)}
+}
+
+func rangesProperty(name string, v Validator, sum func(Range) int) difftest.Property[[]Range] {
+	return difftest.Property[[]Range]{
+		Name:      name,
+		Reference: func(ranges []Range) (int, error) { return sumInvalid(ranges, v), nil },
+		Candidate: func(ranges []Range) (int, error) { return sumRanges(ranges, sum), nil },
+		Generate: func(r *rand.Rand) []Range {
+			ranges := make([]Range, 1+r.Intn(4))
+			for i := range ranges {
+				ranges[i] = randomRange(r)
+			}
+			return ranges
+		},
+		Shrink: func(ranges []Range) [][]Range {
+			return difftest.ShrinkSlice(ranges, func(rg Range) []Range {
+				var out []Range
+				for _, end := range difftest.ShrinkInt(rg.End, rg.Start) {
+					out = append(out, Range{Start: rg.Start, End: end})
+				}
+				return out
+			})
+		},
+		Encode: func(ranges []Range) string { return FormatRanges(ranges) + "\n" },
+		Decode: func(s string) ([]Range, error) { return parseRanges(s) },
+	}
+}
+
+func TestExactlyTwiceMatchesBruteForce(t *testing.T) {
+	difftest.Check(t, rangesProperty("exactly-twice", ExactlyTwiceValidator{}, SumExactlyTwice))
+}
+
+func TestAtLeastTwiceMatchesBruteForce(t *testing.T) {
+	difftest.Check(t, rangesProperty("at-least-twice", AtLeastTwiceValidator{}, SumAtLeastTwice))
+}
+
+func TestExample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(example+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []int{1227775554, 4174379265} {
+		got, err := Parts[i](path)
+		if err != nil {
+			t.Fatalf("part %d: %v", i+1, err)
+		}
+		if got != want {
+			t.Errorf("part %d = %d, want %d", i+1, got, want)
+		}
+	}
+}
+
+// TestHugeRanges checks windows near the top of the int range against the
+// reference, and that a range of ~10^18 IDs is summed without iterating
+func TestHugeRanges(t *testing.T) {
+	for _, r := range []Range{
+		{Start: 999999999999000000, End: 1000000000000100000},      // 18 nines, then 19 digits
+		{Start: 1111111111111111000, End: 1111111111111112000},     // 19 ones
+		{Start: 9223372036854000000, End: 9223372036854775806},     // just below MaxInt
+		{Start: 123456789123456789 - 500, End: 123456789123456789}, // period 9 at length 18
+	} {
+		if got, want := SumExactlyTwice(r), sumInvalid([]Range{r}, ExactlyTwiceValidator{}); got != want {
+			t.Errorf("%v exactly twice: %d, want %d", r, got, want)
+		}
+		if got, want := SumAtLeastTwice(r), sumInvalid([]Range{r}, AtLeastTwiceValidator{}); got != want {
+			t.Errorf("%v at least twice: %d, want %d", r, got, want)
+		}
+	}
+
+	// Every 2-digit repeat is a multiple of 11: 11 + 22 + ... + 99 = 495
+	if got := SumExactlyTwice(Range{Start: 1, End: 9e18}) - SumExactlyTwice(Range{Start: 100, End: 9e18}); got != 495 {
+		t.Errorf("2-digit repeats sum to %d, want 495", got)
+	}
+}
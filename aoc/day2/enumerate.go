@@ -0,0 +1,358 @@
+package day2
+
+import (
+	"math"
+	"slices"
+)
+
+// Repeated-digit IDs can be generated instead of searched for. An ID of
+// length L = k·p made of a p-digit pattern P repeated k times is
+//
+//	P × R(p, k)   where R(p, k) = 1 + 10^p + 10^2p + ... + 10^(k-1)p
+//
+// (123123 = 123 × 1001). For a fixed p and k the IDs inside [lo, hi] are the
+// patterns P in [ceil(lo/R), floor(hi/R)] (and in [10^(p-1), 10^p-1], so the
+// pattern has no leading zero), and their sum is R times an arithmetic series.
+// That makes each range O(digits²) instead of O(hi - lo).
+//
+// Sums past the int range wrap around exactly as the ID-by-ID reference does.
+
+// SumExactlyTwice sums the IDs in r made of a pattern repeated exactly twice
+func SumExactlyTwice(r Range) int {
+	sum := 0
+	for length := 2; length <= maxDigits; length += 2 {
+		sum += sumPeriod(r, length, length/2)
+	}
+	return sum
+}
+
+// SumAtLeastTwice sums the IDs in r made of a pattern repeated two or more times.
+//
+// An ID can repeat several patterns (111111 is 1×6, 11×3 and 111×2), so summing
+// every period would count it more than once. Every proper period of an L-digit
+// ID divides L/q for some prime q | L, and an ID with periods L/q1 and L/q2 also
+// has period L/(q1·q2). Inclusion–exclusion over the prime factors of L therefore
+// counts each ID once.
+func SumAtLeastTwice(r Range) int {
+	sum := 0
+	for length := 2; length <= maxDigits; length++ {
+		primes := primeFactors(length)
+		for subset := 1; subset < 1<<len(primes); subset++ {
+			period, sign := length, -1
+			for i, q := range primes {
+				if subset&(1<<i) != 0 {
+					period /= q
+					sign = -sign
+				}
+			}
+			sum += sign * sumPeriod(r, length, period)
+		}
+	}
+	return sum
+}
+
+// maxDigits is the longest decimal int
+const maxDigits = 19
+
+// sumPeriod sums the length-digit IDs in r that repeat a period-digit pattern
+func sumPeriod(r Range, length, period int) int {
+	lo, hi, ok := clampToLength(r, length)
+	if !ok {
+		return 0
+	}
+
+	repunit := 0
+	for i := 0; i < length/period; i++ {
+		repunit = repunit*pow10[period] + 1
+	}
+
+	first := max(pow10[period-1], ceilDiv(lo, repunit))
+	last := min(pow10[period]-1, hi/repunit)
+	if first > last {
+		return 0
+	}
+	return repunit * seriesSum(first, last)
+}
+
+// clampToLength narrows r to the IDs with exactly length digits
+func clampToLength(r Range, length int) (lo, hi int, ok bool) {
+	lo = max(r.Start, pow10[length-1])
+	hi = r.End
+	if length < maxDigits {
+		hi = min(hi, pow10[length]-1)
+	}
+	return lo, hi, lo <= hi
+}
+
+// seriesSum returns first + (first+1) + ... + last, halving before multiplying
+// so the only overflow is in the result itself
+func seriesSum(first, last int) int {
+	count, total := last-first+1, first+last
+	if count%2 == 0 {
+		return count / 2 * total
+	}
+	return total / 2 * count
+}
+
+// ceilDiv divides a positive a by b, rounding up, without overflowing near the int limit
+func ceilDiv(a, b int) int {
+	return (a-1)/b + 1
+}
+
+// primeFactors returns the distinct prime factors of n in increasing order
+func primeFactors(n int) []int {
+	var primes []int
+	for q := 2; q*q <= n; q++ {
+		if n%q == 0 {
+			primes = append(primes, q)
+			for n%q == 0 {
+				n /= q
+			}
+		}
+	}
+	if n > 1 {
+		primes = append(primes, n)
+	}
+	return primes
+}
+
+// pow10[i] is 10^i for every power that fits in an int
+var pow10 = func() []int {
+	powers := []int{1}
+	for len(powers) < maxDigits {
+		powers = append(powers, powers[len(powers)-1]*10)
+	}
+	return powers
+}()
+
+// RepeatValidator generalises this to any base, zero padding and range of
+// repeat counts. The IDs written with L digits that repeat a p-digit pattern
+// are still P × R(p, L/p), with R in the rule's base and P allowed a leading
+// zero when the IDs are padded to L digits.
+//
+// An ID's periods among the divisors of L are the divisors of L that are
+// multiples of its shortest one (periods p and q dividing L leave a period
+// gcd(p, q)). So the IDs whose shortest period is m follow from the totals for
+// each period d | m by Möbius inversion, the inclusion–exclusion of
+// SumAtLeastTwice over every divisor rather than just the primes. An ID is
+// invalid when L/k is a multiple of its shortest period for some allowed
+// repeat count k.
+
+// enumerable is implemented by validators whose invalid IDs can be counted,
+// summed and listed without checking every ID in a range
+type enumerable interface {
+	// totals counts and sums the invalid IDs in r
+	totals(r Range) (count, sum int)
+	// smallest returns the first n invalid IDs in r in increasing order, or
+	// all of them if n is negative
+	smallest(r Range, n int) []int
+}
+
+func (v ExactlyTwiceValidator) totals(r Range) (count, sum int) {
+	return RepeatValidator{MinRepeats: 2, MaxRepeats: 2}.totals(r)
+}
+
+func (v ExactlyTwiceValidator) smallest(r Range, n int) []int {
+	return RepeatValidator{MinRepeats: 2, MaxRepeats: 2}.smallest(r, n)
+}
+
+func (v AtLeastTwiceValidator) totals(r Range) (count, sum int) {
+	return RepeatValidator{MinRepeats: 2}.totals(r)
+}
+
+func (v AtLeastTwiceValidator) smallest(r Range, n int) []int {
+	return RepeatValidator{MinRepeats: 2}.smallest(r, n)
+}
+
+func (v RepeatValidator) totals(r Range) (count, sum int) {
+	base := radix(v.Base)
+	for _, blk := range blocks(base, v.Width) {
+		lo, hi := max(r.Start, blk.lo), min(r.End, blk.hi)
+		if lo > hi {
+			continue
+		}
+		divs := divisors(blk.length)
+		periods := v.periods(blk.length)
+
+		// Totals of the IDs with each divisor as a period, computed on demand
+		type total struct {
+			count, sum int
+			done       bool
+		}
+		withPeriod := make([]total, len(divs))
+		period := func(i int) total {
+			if t := &withPeriod[i]; !t.done {
+				repunit, first, last := patterns(base, blk, lo, hi, divs[i])
+				if first <= last {
+					t.count, t.sum = last-first+1, repunit*seriesSum(first, last)
+				}
+				t.done = true
+			}
+			return withPeriod[i]
+		}
+
+		for _, m := range divs {
+			if !slices.ContainsFunc(periods, func(p int) bool { return p%m == 0 }) {
+				continue
+			}
+			for i, d := range divs {
+				if m%d != 0 {
+					continue
+				}
+				if mu := mobius(m / d); mu != 0 {
+					t := period(i)
+					count += mu * t.count
+					sum += mu * t.sum
+				}
+			}
+		}
+	}
+	return count, sum
+}
+
+func (v RepeatValidator) smallest(r Range, n int) []int {
+	base := radix(v.Base)
+	var ids []int
+	for _, blk := range blocks(base, v.Width) {
+		if n >= 0 && len(ids) >= n {
+			break
+		}
+		lo, hi := max(r.Start, blk.lo), min(r.End, blk.hi)
+		if lo > hi {
+			continue
+		}
+
+		// Each period lists its IDs in order, so the first n of the union are
+		// among the first n of each
+		var found []int
+		for _, p := range v.periods(blk.length) {
+			repunit, first, last := patterns(base, blk, lo, hi, p)
+			for pattern, listed := first, 0; pattern <= last && (n < 0 || listed < n-len(ids)); pattern, listed = pattern+1, listed+1 {
+				found = append(found, pattern*repunit)
+			}
+		}
+		slices.Sort(found)
+		found = slices.Compact(found)
+		if n >= 0 {
+			found = found[:min(len(found), n-len(ids))]
+		}
+		ids = append(ids, found...)
+	}
+	return ids
+}
+
+// periods returns the pattern lengths L/k for the repeat counts k the rule
+// allows in an L-digit ID
+func (v RepeatValidator) periods(length int) []int {
+	maxRepeats := length
+	if v.MaxRepeats > 0 {
+		maxRepeats = min(maxRepeats, v.MaxRepeats)
+	}
+	var periods []int
+	for k := max(v.MinRepeats, 2); k <= maxRepeats; k++ {
+		if length%k == 0 {
+			periods = append(periods, length/k)
+		}
+	}
+	return periods
+}
+
+// block is the non-negative IDs [lo, hi] written with exactly length digits;
+// padded ones are those zero-padded to the rule's width, whose patterns may
+// start with a zero
+type block struct {
+	length, lo, hi int
+	padded         bool
+}
+
+// blocks splits the non-negative ints by how many digits they are written
+// with in base, padded to width
+func blocks(base, width int) []block {
+	width = min(width, len(digits{}.buf)) // digitsOf pads to at most this many
+	var out []block
+	for length := max(width, 1); ; length++ {
+		b := block{length: length, hi: math.MaxInt, padded: length == width}
+		if !b.padded {
+			lo, ok := power(base, length-1)
+			if !ok {
+				break
+			}
+			b.lo = lo
+		}
+		if p, ok := power(base, length); ok {
+			b.hi = p - 1
+		}
+		out = append(out, b)
+		if b.hi == math.MaxInt {
+			break
+		}
+	}
+	return out
+}
+
+// patterns returns R(p, L/p) in base for the block's length L, and the
+// patterns P in [first, last] for which P × R is an ID of the block in
+// [lo, hi]; first > last if there are none. R is 0 when it does not fit in an
+// int, which leaves only the all-zero pattern of a padded block.
+func patterns(base int, blk block, lo, hi, period int) (repunit, first, last int) {
+	step, fits := power(base, period)
+	for i := 0; i < blk.length/period; i++ {
+		if repunit > 0 && (!fits || repunit > (math.MaxInt-1)/step) {
+			if blk.padded && lo == 0 {
+				return 0, 0, 0
+			}
+			return 0, 1, 0
+		}
+		repunit = repunit*step + 1
+	}
+
+	first = lo / repunit
+	if lo%repunit != 0 {
+		first++
+	}
+	if !blk.padded {
+		smallest, _ := power(base, period-1) // below the block's lo, so it fits
+		first = max(first, smallest)
+	}
+	last = hi / repunit
+	if fits {
+		last = min(last, step-1)
+	}
+	return repunit, first, last
+}
+
+// power returns base^exp, or math.MaxInt and false if that does not fit in an int
+func power(base, exp int) (int, bool) {
+	p := 1
+	for ; exp > 0; exp-- {
+		if p > math.MaxInt/base {
+			return math.MaxInt, false
+		}
+		p *= base
+	}
+	return p, true
+}
+
+// divisors returns the divisors of n in increasing order
+func divisors(n int) []int {
+	var divs []int
+	for d := 1; d <= n; d++ {
+		if n%d == 0 {
+			divs = append(divs, d)
+		}
+	}
+	return divs
+}
+
+// mobius is the Möbius function: 0 if n has a square factor, otherwise -1 or
+// 1 for an odd or even number of prime factors
+func mobius(n int) int {
+	mu := 1
+	for _, q := range primeFactors(n) {
+		if n%(q*q) == 0 {
+			return 0
+		}
+		mu = -mu
+	}
+	return mu
+}
@@ -0,0 +1,33 @@
+package day2
+
+import (
+	"slices"
+	"testing"
+)
+
+func FuzzParseRanges(f *testing.F) {
+	for _, seed := range []string{
+		"11-22,95-115,998-1012,1188511880-1188511890,222220-222224,1698522-1698528,446443-446449,38593856-38593862,565653-565659,824824821-824824827,2121212118-2121212124",
+		"11-22,",
+		" 1 - 2 , 3-4 ",
+		"5-5",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		ranges, err := parseRanges(line)
+		if err != nil || len(ranges) == 0 {
+			return
+		}
+
+		formatted := FormatRanges(ranges)
+		again, err := parseRanges(formatted)
+		if err != nil {
+			t.Fatalf("reparsing %q (from %q): %v", formatted, line, err)
+		}
+		if !slices.Equal(again, ranges) {
+			t.Fatalf("round trip of %q: got %v, want %v", line, again, ranges)
+		}
+	})
+}
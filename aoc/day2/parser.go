@@ -7,11 +7,22 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"adv2025/aoc/interval"
 )
 
-// Range represents a product ID range with start and end values
-type Range struct {
-	Start, End int
+// Range represents a product ID range with start and end values. It is the
+// shared interval type, so it formats as "11-22" (also in JSON) and ranges can
+// be collected into an interval.Set
+type Range = interval.Interval
+
+// FormatRanges formats ranges as a comma-separated input line, the inverse of parseRanges
+func FormatRanges(ranges []Range) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
 }
 
 // RangeParser reads and parses product ID ranges from input
@@ -6,26 +6,19 @@ import "fmt"
 //
 // Problem: Find IDs that are patterns repeated exactly twice (e.g., 123123, 55, 6464)
 //
-// Algorithm Analysis:
-// - Must check each ID individually - no mathematical shortcut exists
-// - Can't skip ranges: pattern 123123 appears scattered throughout
-// - Can't generate patterns: would need to enumerate all patterns then check ranges
+// Algorithm: generate the invalid IDs instead of testing every ID.
+// An ID repeated twice is pattern × (10^p + 1) (123123 = 123 × 1001), so for
+// each pattern length p the invalid IDs in a range are a contiguous run of
+// patterns, and their sum is (10^p + 1) times an arithmetic series.
+// See enumerate.go for the details.
 //
-// Why this approach is actually efficient:
-// 1. Validator.IsInvalid() is O(log n) where n is the ID value (digit count)
-// 2. String comparison in Go is highly optimized (memcmp)
-// 3. Early exits in validator for odd-length and mismatches
-// 4. Strategy pattern allows swapping validators without changing this code
+// Why not check every ID:
+// - Checking is O(range size): fine at ~50ms for the puzzle input, hopeless for
+//   ranges spanning billions of IDs near 10^18
+// - Enumeration is O(digits) per range, whatever its size
 //
-// Performance: ~56ms for millions of IDs is reasonable
-// - Each ID check: ~50-100ns (string conversion + comparison)
-// - Alternative approaches (pattern generation, math) are actually slower
-// - This is as fast as "check every number" can get in Go
-//
-// Potential micro-optimizations (not worth the complexity):
-// - Parallel goroutines per range (overhead > benefit for this problem)
-// - Digit-by-digit validation (requires complex logic, slower than string ops)
-// - Caching (patterns don't repeat enough to matter)
+// The ID-by-ID path survives in the tests as sumInvalid with
+// ExactlyTwiceValidator, the reference the enumeration is checked against.
 func Part1(inputPath string) (int, error) {
 	parser, err := FromFile(inputPath)
 	if err != nil {
@@ -37,20 +30,9 @@ func Part1(inputPath string) (int, error) {
 		return 0, fmt.Errorf("parsing ranges: %w", err)
 	}
 
-	// Strategy pattern: validator encapsulates the validation logic
-	// This keeps Part1 focused on iteration, validator focused on rules
-	validator := ExactlyTwiceValidator{}
 	sum := 0
-
-	// Iterate through all IDs in all ranges
-	// Time complexity: O(total_range_size * log(max_id))
-	// Space complexity: O(1) - only accumulator
 	for _, r := range ranges {
-		for id := r.Start; id <= r.End; id++ {
-			if validator.IsInvalid(id) {
-				sum += id
-			}
-		}
+		sum += SumExactlyTwice(r)
 	}
 
 	return sum, nil
@@ -6,16 +6,14 @@ import "fmt"
 //
 // Problem: Find IDs that are patterns repeated at least twice (e.g., 111, 123123, 55)
 //
-// Same algorithmic approach as Part1, but with different validation rules.
-// See Part1 comments for detailed analysis of why iteration is optimal here.
+// Same enumeration as Part1, but over every pattern length that divides the ID
+// length. The catch is that one ID can repeat several patterns: 111111 is "1"
+// six times, "11" three times and "111" twice, so summing each pattern length
+// separately would count it three times. Inclusion–exclusion over the prime
+// factors of the ID length removes the duplicates (see SumAtLeastTwice).
 //
-// Strategy Pattern Benefit: We only changed the validator, not the iteration logic.
-// Part1 and Part2 share the same structure but different behavior - classic OOP pattern.
-//
-// Performance: Similar to Part1 (~56ms)
-// - AtLeastTwiceValidator is slightly more complex (tries multiple pattern lengths)
-// - Still O(log n) per ID check (where n is the ID value)
-// - Early exits keep average case fast
+// The ID-by-ID path survives in the tests as sumInvalid with
+// AtLeastTwiceValidator, the reference the enumeration is checked against.
 func Part2(inputPath string) (int, error) {
 	parser, err := FromFile(inputPath)
 	if err != nil {
@@ -27,17 +25,9 @@ func Part2(inputPath string) (int, error) {
 		return 0, fmt.Errorf("parsing ranges: %w", err)
 	}
 
-	// Different validator, same iteration pattern
-	// This demonstrates the power of the Strategy pattern
-	validator := AtLeastTwiceValidator{}
 	sum := 0
-
 	for _, r := range ranges {
-		for id := r.Start; id <= r.End; id++ {
-			if validator.IsInvalid(id) {
-				sum += id
-			}
-		}
+		sum += SumAtLeastTwice(r)
 	}
 
 	return sum, nil
@@ -0,0 +1,153 @@
+package day2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// PatternMatcher is implemented by validators that can say which pattern made an
+// ID invalid; RepeatValidator reports the shortest repeated pattern's length
+type PatternMatcher interface {
+	PatternLength(id int) int
+}
+
+// Match is one invalid ID in a report
+type Match struct {
+	ID            int `json:"id"`
+	PatternLength int `json:"pattern_length,omitempty"` // 0 when the rule has no pattern
+}
+
+// RangeReport is the breakdown of a single input range
+type RangeReport struct {
+	Range     Range   `json:"range"`
+	Count     int     `json:"count"`
+	Sum       int     `json:"sum"`
+	Matches   []Match `json:"matches"`             // the first invalid IDs, up to the report limit
+	Truncated bool    `json:"truncated,omitempty"` // more IDs matched than are listed
+	Overlaps  []int   `json:"overlaps,omitempty"`  // indices of other ranges sharing IDs with this one
+}
+
+// Report lists, range by range, the IDs one rule finds invalid
+type Report struct {
+	Rule   string        `json:"rule"`
+	Count  int           `json:"count"`
+	Sum    int           `json:"sum"`
+	Ranges []RangeReport `json:"ranges"`
+}
+
+// NewReport finds the IDs v reports invalid in each range, listing at most
+// limit matches per range (a negative limit lists them all). Ranges that
+// overlap are flagged, since the same IDs then add to the total more than once,
+// exactly as they do in Part1 and Part2.
+//
+// Repeat rules are counted and summed by enumeration (see enumerate.go), and
+// only the listed IDs are generated, so ranges near 10^18 cost no more than
+// short ones. Any other rule checks every ID in the range.
+func NewReport(ranges []Range, v Validator, limit int) Report {
+	matcher, _ := v.(PatternMatcher)
+	overlaps := findOverlaps(ranges)
+
+	report := Report{Rule: fmt.Sprint(v), Ranges: make([]RangeReport, len(ranges))}
+	for i, r := range ranges {
+		rr := RangeReport{Range: r, Matches: []Match{}, Overlaps: overlaps[i]}
+		var ids []int
+		if e, ok := v.(enumerable); ok {
+			rr.Count, rr.Sum = e.totals(r)
+			ids = e.smallest(r, limit)
+		} else {
+			for id := r.Start; id <= r.End; id++ {
+				if !v.IsInvalid(id) {
+					continue
+				}
+				rr.Count++
+				rr.Sum += id
+				if limit < 0 || len(ids) < limit {
+					ids = append(ids, id)
+				}
+			}
+		}
+		for _, id := range ids {
+			m := Match{ID: id}
+			if matcher != nil {
+				m.PatternLength = matcher.PatternLength(id)
+			}
+			rr.Matches = append(rr.Matches, m)
+		}
+		rr.Truncated = rr.Count > len(rr.Matches)
+		report.Count += rr.Count
+		report.Sum += rr.Sum
+		report.Ranges[i] = rr
+	}
+	return report
+}
+
+// findOverlaps returns, for each range, the indices of the other ranges it
+// shares at least one ID with. Sorting by start means each range only needs
+// comparing with the ones after it that start before it ends.
+func findOverlaps(ranges []Range) [][]int {
+	order := make([]int, len(ranges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return ranges[order[a]].Start < ranges[order[b]].Start })
+
+	overlaps := make([][]int, len(ranges))
+	for a, i := range order {
+		for _, j := range order[a+1:] {
+			if ranges[j].Start > ranges[i].End {
+				break
+			}
+			if ranges[j].End >= ranges[j].Start && ranges[i].End >= ranges[i].Start {
+				overlaps[i] = append(overlaps[i], j)
+				overlaps[j] = append(overlaps[j], i)
+			}
+		}
+	}
+	for _, o := range overlaps {
+		sort.Ints(o)
+	}
+	return overlaps
+}
+
+// WriteJSON writes the report as an indented JSON document
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTable writes the report as an aligned text table, one row per range.
+// Matches are shown as ID(pattern length); overlapping ranges are listed by
+// their position in the input (#1 is the first range).
+func (r Report) WriteTable(w io.Writer) error {
+	fmt.Fprintf(w, "Rule %s: %d invalid IDs, sum %d\n", r.Rule, r.Count, r.Sum)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tRANGE\tCOUNT\tSUM\tINVALID IDS\tOVERLAPS")
+	for i, rr := range r.Ranges {
+		ids := make([]string, len(rr.Matches))
+		for j, m := range rr.Matches {
+			ids[j] = strconv.Itoa(m.ID)
+			if m.PatternLength > 0 {
+				ids[j] += fmt.Sprintf("(%d)", m.PatternLength)
+			}
+		}
+		if rr.Truncated {
+			ids = append(ids, fmt.Sprintf("... %d more", rr.Count-len(rr.Matches)))
+		}
+
+		overlaps := make([]string, len(rr.Overlaps))
+		for j, o := range rr.Overlaps {
+			overlaps[j] = fmt.Sprintf("#%d", o+1)
+		}
+
+		fmt.Fprintf(tw, "%d\t%v\t%d\t%d\t%s\t%s\n",
+			i+1, rr.Range, rr.Count, rr.Sum, strings.Join(ids, " "), strings.Join(overlaps, " "))
+	}
+	return tw.Flush()
+}
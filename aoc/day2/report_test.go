@@ -0,0 +1,114 @@
+package day2
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReportMatchesParts(t *testing.T) {
+	ranges, err := parseRanges(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := NewReport(ranges, RepeatValidator{MinRepeats: 2}, -1)
+	if report.Sum != 4174379265 || report.Count != 13 {
+		t.Errorf("report totals %d IDs, sum %d; want 13, 4174379265", report.Count, report.Sum)
+	}
+
+	// 998-1012 holds 999 ("9" three times) and 1010 ("10" twice)
+	if got, want := report.Ranges[2].Matches, []Match{{999, 1}, {1010, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("998-1012 matches = %v, want %v", got, want)
+	}
+	for _, rr := range report.Ranges {
+		if len(rr.Overlaps) != 0 {
+			t.Errorf("%v: unexpected overlaps %v", rr.Range, rr.Overlaps)
+		}
+	}
+}
+
+func TestReportTruncates(t *testing.T) {
+	report := NewReport([]Range{{Start: 10, End: 100}}, RepeatValidator{MinRepeats: 2}, 3)
+	rr := report.Ranges[0]
+	if rr.Count != 9 || len(rr.Matches) != 3 || !rr.Truncated {
+		t.Errorf("got count %d, %d matches, truncated %v; want 9, 3, true", rr.Count, len(rr.Matches), rr.Truncated)
+	}
+	if rr.Sum != 495 {
+		t.Errorf("sum = %d, want 495 even though only 3 IDs are listed", rr.Sum)
+	}
+
+	var table bytes.Buffer
+	if err := report.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(table.String(), "11(1) 22(1) 33(1) ... 6 more") {
+		t.Errorf("table does not list the truncated IDs:\n%s", table.String())
+	}
+}
+
+func TestReportHugeRange(t *testing.T) {
+	huge := Range{Start: 100_000_000_000_000_000, End: 1_000_000_000_000_000_000}
+	report := NewReport([]Range{huge}, RepeatValidator{MinRepeats: 2}, 3)
+	rr := report.Ranges[0]
+	if rr.Sum != SumAtLeastTwice(huge) || !rr.Truncated {
+		t.Errorf("sum %d, truncated %v; want %d, true", rr.Sum, rr.Truncated, SumAtLeastTwice(huge))
+	}
+	want := []Match{{100000000100000000, 9}, {100000001100000001, 9}, {100000002100000002, 9}}
+	if !reflect.DeepEqual(rr.Matches, want) {
+		t.Errorf("matches = %v, want %v", rr.Matches, want)
+	}
+}
+
+func TestFindOverlapsMatchesPairwise(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		ranges := make([]Range, r.Intn(10))
+		for i := range ranges {
+			start := r.Intn(100)
+			ranges[i] = Range{Start: start, End: start + r.Intn(20) - 2} // a few are empty
+		}
+
+		want := make([][]int, len(ranges))
+		for i, a := range ranges {
+			for j, b := range ranges {
+				if i != j && a.Start <= a.End && b.Start <= b.End && a.Start <= b.End && b.Start <= a.End {
+					want[i] = append(want[i], j)
+				}
+			}
+		}
+		if got := findOverlaps(ranges); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%v: got %v, want %v", ranges, got, want)
+		}
+	}
+}
+
+func TestReportJSON(t *testing.T) {
+	report := NewReport([]Range{{Start: 11, End: 22}, {Start: 20, End: 30}}, PalindromeValidator{}, -1)
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Rule   string `json:"rule"`
+		Ranges []struct {
+			Range    string  `json:"range"`
+			Matches  []Match `json:"matches"`
+			Overlaps []int   `json:"overlaps"`
+		} `json:"ranges"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decoding %s: %v", buf.String(), err)
+	}
+	if decoded.Rule != "palindrome" || decoded.Ranges[0].Range != "11-22" || !reflect.DeepEqual(decoded.Ranges[1].Overlaps, []int{0}) {
+		t.Errorf("decoded %+v", decoded)
+	}
+	// Palindromes have no pattern length
+	if got, want := decoded.Ranges[0].Matches, []Match{{ID: 11}, {ID: 22}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("matches = %v, want %v", got, want)
+	}
+}
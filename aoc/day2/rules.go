@@ -0,0 +1,242 @@
+package day2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// digits holds an ID's digits, most significant first, without allocating.
+// 64 digits is enough for any int in base 2.
+type digits struct {
+	buf [64]byte
+	n   int
+}
+
+// digitsOf writes id in the given base, padded with leading zeros to width digits
+func digitsOf(id, base, width int) digits {
+	var d digits
+	i := len(d.buf)
+	for id > 0 || i == len(d.buf) {
+		i--
+		d.buf[i] = byte(id % base)
+		id /= base
+	}
+	for len(d.buf)-i < width && i > 0 {
+		i--
+		d.buf[i] = 0
+	}
+	copy(d.buf[:], d.buf[i:])
+	d.n = len(d.buf) - i
+	return d
+}
+
+// hasPeriod reports whether the digits are a pattern of the given length repeated
+func (d *digits) hasPeriod(period int) bool {
+	for i := period; i < d.n; i++ {
+		if d.buf[i] != d.buf[i-period] {
+			return false
+		}
+	}
+	return true
+}
+
+// RepeatValidator reports IDs whose digits are a pattern repeated k times, for
+// some k between MinRepeats and MaxRepeats. ExactlyTwiceValidator is
+// RepeatValidator{MinRepeats: 2, MaxRepeats: 2} and AtLeastTwiceValidator is
+// RepeatValidator{MinRepeats: 2}.
+type RepeatValidator struct {
+	MinRepeats int // at least 2 repeats are always required
+	MaxRepeats int // 0 means no upper limit
+	Base       int // 2 to 36 (others are clamped into it); 0 means 10
+	Width      int // pad IDs with leading zeros to this many digits (0 = no padding)
+}
+
+// IsInvalid returns true if the ID's digits repeat a pattern an allowed number of times
+func (v RepeatValidator) IsInvalid(id int) bool {
+	return v.PatternLength(id) > 0
+}
+
+// PatternLength returns the length of the shortest pattern whose allowed repetition
+// makes up the ID's digits, or 0 if there is none (111111 at least twice is "1")
+func (v RepeatValidator) PatternLength(id int) int {
+	if id < 0 {
+		return 0
+	}
+	d := digitsOf(id, radix(v.Base), v.Width)
+
+	maxRepeats := d.n
+	if v.MaxRepeats > 0 {
+		maxRepeats = min(maxRepeats, v.MaxRepeats)
+	}
+	// More repeats means a shorter pattern, so try them first
+	for k := maxRepeats; k >= max(v.MinRepeats, 2); k-- {
+		if d.n%k == 0 && d.hasPeriod(d.n/k) {
+			return d.n / k
+		}
+	}
+	return 0
+}
+
+func (v RepeatValidator) String() string {
+	var spec string
+	switch {
+	case v.MaxRepeats > 0 && v.MaxRepeats == max(v.MinRepeats, 2):
+		spec = fmt.Sprintf("exactly=%d", v.MaxRepeats)
+	case v.MaxRepeats > 0:
+		spec = fmt.Sprintf("repeats=%d-%d", max(v.MinRepeats, 2), v.MaxRepeats)
+	default:
+		spec = fmt.Sprintf("at-least=%d", max(v.MinRepeats, 2))
+	}
+	return spec + formatOptions(v.Base, v.Width)
+}
+
+// PalindromeValidator reports IDs whose digits read the same in both directions
+// (single digits included)
+type PalindromeValidator struct {
+	Base  int // 2 to 36 (others are clamped into it); 0 means 10
+	Width int // pad IDs with leading zeros to this many digits (0 = no padding)
+}
+
+// IsInvalid returns true if the ID's digits are a palindrome
+func (v PalindromeValidator) IsInvalid(id int) bool {
+	if id < 0 {
+		return false
+	}
+	d := digitsOf(id, radix(v.Base), v.Width)
+	for i, j := 0, d.n-1; i < j; i, j = i+1, j-1 {
+		if d.buf[i] != d.buf[j] {
+			return false
+		}
+	}
+	return true
+}
+
+func (v PalindromeValidator) String() string {
+	return "palindrome" + formatOptions(v.Base, v.Width)
+}
+
+// radix is the base a validator writes IDs in: 10 for 0, and any other base
+// outside 2 to 36 clamped into it, so that a validator built directly with
+// Base 1 or a negative base still terminates
+func radix(base int) int {
+	if base == 0 {
+		return 10
+	}
+	return min(max(base, 2), 36)
+}
+
+func formatOptions(base, width int) string {
+	var opts string
+	if base = radix(base); base != 10 {
+		opts += fmt.Sprintf(" base=%d", base)
+	}
+	if width > 0 {
+		opts += fmt.Sprintf(" width=%d", width)
+	}
+	return opts
+}
+
+// ParseRule builds a validator from a rule such as "exactly=2", "at-least=3 base=16",
+// "repeats=2-4" or "palindrome width=6". The first word picks the rule; the optional
+// base= (2-36) and width= (zero-padded digits) settings follow it.
+func ParseRule(spec string) (Validator, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty rule")
+	}
+
+	base, width := 0, 0
+	for _, opt := range fields[1:] {
+		key, value, ok := strings.Cut(opt, "=")
+		n, err := strconv.Atoi(value)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("rule %q: invalid option %q", spec, opt)
+		}
+		switch key {
+		case "base":
+			if n < 2 || n > 36 {
+				return nil, fmt.Errorf("rule %q: base must be between 2 and 36", spec)
+			}
+			base = n
+		case "width":
+			if n < 0 || n > 64 {
+				return nil, fmt.Errorf("rule %q: width must be between 0 and 64", spec)
+			}
+			width = n
+		default:
+			return nil, fmt.Errorf("rule %q: unknown option %q", spec, key)
+		}
+	}
+
+	kind, arg, _ := strings.Cut(fields[0], "=")
+	switch kind {
+	case "palindrome":
+		if arg != "" {
+			return nil, fmt.Errorf("rule %q: palindrome takes no count", spec)
+		}
+		return PalindromeValidator{Base: base, Width: width}, nil
+	case "exactly", "at-least":
+		k, err := strconv.Atoi(arg)
+		if err != nil || k < 2 {
+			return nil, fmt.Errorf("rule %q: repeat count must be a number of at least 2", spec)
+		}
+		v := RepeatValidator{MinRepeats: k, Base: base, Width: width}
+		if kind == "exactly" {
+			v.MaxRepeats = k
+		}
+		return v, nil
+	case "repeats":
+		lo, hi, ok := strings.Cut(arg, "-")
+		minK, err1 := strconv.Atoi(lo)
+		maxK, err2 := strconv.Atoi(hi)
+		if !ok || err1 != nil || err2 != nil || minK < 2 || maxK < minK {
+			return nil, fmt.Errorf("rule %q: repeats needs a range like 2-4", spec)
+		}
+		return RepeatValidator{MinRepeats: minK, MaxRepeats: maxK, Base: base, Width: width}, nil
+	default:
+		return nil, fmt.Errorf("rule %q: unknown rule %q", spec, kind)
+	}
+}
+
+// Evaluation is the result of one rule over a set of ranges
+type Evaluation struct {
+	Rule  Validator
+	Count int // invalid IDs found
+	Sum   int // their total
+}
+
+// Evaluate counts and sums the invalid IDs in the ranges for each rule. Repeat
+// rules are counted by enumeration (see enumerate.go), whatever the ranges'
+// width; any other rule checks every ID, all of them in a single pass.
+func Evaluate(ranges []Range, rules []Validator) []Evaluation {
+	results := make([]Evaluation, len(rules))
+	var checked []int // rules that have to check each ID
+	for i, rule := range rules {
+		results[i].Rule = rule
+		e, ok := rule.(enumerable)
+		if !ok {
+			checked = append(checked, i)
+			continue
+		}
+		for _, r := range ranges {
+			count, sum := e.totals(r)
+			results[i].Count += count
+			results[i].Sum += sum
+		}
+	}
+	if len(checked) == 0 {
+		return results
+	}
+	for _, r := range ranges {
+		for id := r.Start; id <= r.End; id++ {
+			for _, i := range checked {
+				if rules[i].IsInvalid(id) {
+					results[i].Count++
+					results[i].Sum += id
+				}
+			}
+		}
+	}
+	return results
+}
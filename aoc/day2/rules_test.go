@@ -0,0 +1,219 @@
+package day2
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// stringRepeats is the string-based reference for RepeatValidator
+func stringRepeats(id int, v RepeatValidator) bool {
+	s := strconv.FormatInt(int64(id), radix(v.Base))
+	if len(s) < v.Width {
+		s = strings.Repeat("0", v.Width-len(s)) + s
+	}
+	for k := max(v.MinRepeats, 2); k <= len(s); k++ {
+		if v.MaxRepeats > 0 && k > v.MaxRepeats {
+			break
+		}
+		if len(s)%k == 0 && strings.Repeat(s[:len(s)/k], k) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRepeatValidatorMatchesTwiceValidators(t *testing.T) {
+	exactly := RepeatValidator{MinRepeats: 2, MaxRepeats: 2}
+	atLeast := RepeatValidator{MinRepeats: 2}
+	check := func(id int) {
+		if exactly.IsInvalid(id) != (ExactlyTwiceValidator{}).IsInvalid(id) {
+			t.Fatalf("exactly twice disagrees on %d", id)
+		}
+		if atLeast.IsInvalid(id) != (AtLeastTwiceValidator{}).IsInvalid(id) {
+			t.Fatalf("at least twice disagrees on %d", id)
+		}
+	}
+	for id := 0; id < 200000; id++ {
+		check(id)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		// Build repeats deliberately, as random IDs almost never are
+		pattern := strconv.Itoa(1 + r.Intn(999))
+		id, _ := strconv.Atoi(strings.Repeat(pattern, 2+r.Intn(3)))
+		check(id)
+		check(id + 1)
+	}
+}
+
+func TestRepeatValidatorBasesAndWidths(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 20000; i++ {
+		v := RepeatValidator{
+			MinRepeats: 2 + r.Intn(3),
+			Base:       2 + r.Intn(35),
+			Width:      r.Intn(12),
+		}
+		if r.Intn(2) == 0 {
+			v.MaxRepeats = v.MinRepeats + r.Intn(3)
+		}
+		id := r.Intn(1 << (1 + r.Intn(20)))
+		if got, want := v.IsInvalid(id), stringRepeats(id, v); got != want {
+			t.Fatalf("%+v on %d (%s): got %v, want %v", v, id, strconv.FormatInt(int64(id), v.Base), got, want)
+		}
+	}
+}
+
+func TestPalindromeValidator(t *testing.T) {
+	for _, tc := range []struct {
+		v    PalindromeValidator
+		id   int
+		want bool
+	}{
+		{PalindromeValidator{}, 7, true},
+		{PalindromeValidator{}, 12321, true},
+		{PalindromeValidator{}, 1230, false},
+		{PalindromeValidator{Width: 5}, 1210, true}, // 01210
+		{PalindromeValidator{Width: 4}, 121, false}, // 0121
+		{PalindromeValidator{Width: 4}, 110, true},  // 0110
+		{PalindromeValidator{Base: 2}, 0b1001, true},
+		{PalindromeValidator{Base: 16}, 0xabba, true},
+		{PalindromeValidator{Base: 16}, 0xabbc, false},
+	} {
+		if got := tc.v.IsInvalid(tc.id); got != tc.want {
+			t.Errorf("%v on %d = %v, want %v", tc.v, tc.id, got, tc.want)
+		}
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	for spec, want := range map[string]Validator{
+		"exactly=2":           RepeatValidator{MinRepeats: 2, MaxRepeats: 2},
+		"at-least=3 base=16":  RepeatValidator{MinRepeats: 3, Base: 16},
+		"repeats=2-4 width=8": RepeatValidator{MinRepeats: 2, MaxRepeats: 4, Width: 8},
+		"palindrome base=2":   PalindromeValidator{Base: 2},
+		"palindrome width=6":  PalindromeValidator{Width: 6},
+	} {
+		got, err := ParseRule(spec)
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", spec, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseRule(%q) = %#v, want %#v", spec, got, want)
+		}
+		if again, err := ParseRule(fmt.Sprint(got)); err != nil || again != got {
+			t.Errorf("%q does not round-trip through String: %v, %v", spec, again, err)
+		}
+	}
+
+	for _, bad := range []string{"", "exactly", "exactly=1", "at-least=x", "repeats=4-2", "palindrome=2", "sometimes", "exactly=2 base=37", "exactly=2 colour=red"} {
+		if _, err := ParseRule(bad); err == nil {
+			t.Errorf("ParseRule(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestEvaluateMatchesSumInvalid(t *testing.T) {
+	ranges, err := parseRanges(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []Validator{
+		RepeatValidator{MinRepeats: 2, MaxRepeats: 2},
+		RepeatValidator{MinRepeats: 2},
+		RepeatValidator{MinRepeats: 3, Base: 2},
+		PalindromeValidator{},
+	}
+	for _, e := range Evaluate(ranges, rules) {
+		if want := sumInvalid(ranges, e.Rule); e.Sum != want {
+			t.Errorf("%v: sum %d, want %d", e.Rule, e.Sum, want)
+		}
+	}
+}
+
+// randomRepeatValidator picks a repeat rule with any base, padding and counts
+func randomRepeatValidator(r *rand.Rand) RepeatValidator {
+	v := RepeatValidator{MinRepeats: 2 + r.Intn(3), Base: 2 + r.Intn(35), Width: r.Intn(12)}
+	if r.Intn(2) == 0 {
+		v.MaxRepeats = v.MinRepeats + r.Intn(3)
+	}
+	return v
+}
+
+func TestRepeatEnumerationMatchesIDByID(t *testing.T) {
+	r := rand.New(rand.NewSource(36))
+	for i := 0; i < 5000; i++ {
+		v := randomRepeatValidator(r)
+		var rg Range
+		switch r.Intn(3) {
+		case 0: // small IDs, where padding matters
+			rg.Start = r.Intn(1000)
+		case 1: // anywhere
+			rg.Start = r.Intn(1 << (1 + r.Intn(62)))
+		default: // just below the int limit, where R and the sums overflow
+			rg.Start = math.MaxInt - 1 - r.Intn(1<<(1+r.Intn(20)))
+		}
+		rg.End = min(rg.Start+r.Intn(3000), math.MaxInt-1)
+
+		var ids []int
+		sum := 0
+		for id := rg.Start; id <= rg.End; id++ {
+			if v.IsInvalid(id) {
+				ids = append(ids, id)
+				sum += id
+			}
+		}
+		if count, got := v.totals(rg); count != len(ids) || got != sum {
+			t.Fatalf("%v on %v: totals %d, %d; want %d, %d", v, rg, count, got, len(ids), sum)
+		}
+		n := r.Intn(5) - 1
+		want := ids
+		if n >= 0 {
+			want = ids[:min(n, len(ids))]
+		}
+		if got := v.smallest(rg, n); !slices.Equal(got, want) && len(got)+len(want) > 0 {
+			t.Fatalf("%v on %v: smallest %d = %v, want %v", v, rg, n, got, want)
+		}
+	}
+}
+
+func TestEvaluateHugeRange(t *testing.T) {
+	huge := []Range{{Start: 1, End: 1_000_000_000_000_000_000}}
+	results := Evaluate(huge, []Validator{
+		ExactlyTwiceValidator{},
+		RepeatValidator{MinRepeats: 2},
+		RepeatValidator{MinRepeats: 3, Base: 2, Width: 8},
+	})
+	if got, want := results[0].Sum, SumExactlyTwice(huge[0]); got != want {
+		t.Errorf("exactly twice: sum %d, want %d", got, want)
+	}
+	if got, want := results[1].Sum, SumAtLeastTwice(huge[0]); got != want {
+		t.Errorf("at least twice: sum %d, want %d", got, want)
+	}
+	if results[2].Count == 0 {
+		t.Errorf("%v found no IDs", results[2].Rule)
+	}
+}
+
+func TestOutOfRangeBaseIsClamped(t *testing.T) {
+	for id := 0; id < 1000; id++ {
+		if got, want := (RepeatValidator{MinRepeats: 2, Base: 1}).IsInvalid(id), (RepeatValidator{MinRepeats: 2, Base: 2}).IsInvalid(id); got != want {
+			t.Fatalf("base 1 on %d = %v, want base 2's %v", id, got, want)
+		}
+		if got, want := (PalindromeValidator{Base: -4}).IsInvalid(id), (PalindromeValidator{Base: 2}).IsInvalid(id); got != want {
+			t.Fatalf("base -4 on %d = %v, want base 2's %v", id, got, want)
+		}
+		if got, want := (PalindromeValidator{Base: 99}).IsInvalid(id), (PalindromeValidator{Base: 36}).IsInvalid(id); got != want {
+			t.Fatalf("base 99 on %d = %v, want base 36's %v", id, got, want)
+		}
+	}
+	if got := fmt.Sprint(RepeatValidator{MinRepeats: 2, Base: 1}); got != "at-least=2 base=2" {
+		t.Errorf("String = %q, want the base it uses", got)
+	}
+}
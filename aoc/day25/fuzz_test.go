@@ -0,0 +1,43 @@
+package day25
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// maxFuzzInput is the longest input FuzzParseGraph tries, well above the seeds
+const maxFuzzInput = 512
+
+func FuzzParseGraph(f *testing.F) {
+	for _, seed := range []string{
+		"START-A:10\nSTART-B:15\nA-C:5\nB-C:8\nB-D:12\nC-REACTOR_1:20\nD-REACTOR_2:15\nD-E:10\nE-REACTOR_3:25\n",
+		"START-A:10\nA-REACTOR_1:5\nSTART-B:20\nB-REACTOR_1:5\n",
+		"A-A:0",
+		"A-B:1\nB-A:2",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		// The fuzzer minimises every new input that finds new coverage, in time
+		// quadratic in its length; inputs many kilobytes long stall it for a
+		// minute each without reaching anything a short one cannot
+		if len(input) > maxFuzzInput {
+			return
+		}
+		graph, err := ParseGraph(strings.NewReader(input))
+		if err != nil {
+			return
+		}
+
+		formatted := graph.Format()
+		again, err := ParseGraph(strings.NewReader(formatted))
+		if err != nil {
+			t.Fatalf("reparsing %q (from %q): %v", formatted, input, err)
+		}
+		if !reflect.DeepEqual(again, graph) {
+			t.Fatalf("round trip of %q: got %v, want %v", input, again, graph)
+		}
+	})
+}
@@ -32,7 +32,7 @@ func ParseGraph(r io.Reader) (Graph, error) {
 		}
 
 		cost, err := strconv.Atoi(parts[1])
-		if err != nil {
+		if err != nil || cost < 0 {
 			return nil, fmt.Errorf("invalid cost: %s", parts[1])
 		}
 
@@ -72,7 +72,7 @@ func ParseDAG(r io.Reader) (Graph, error) {
 		}
 
 		cost, err := strconv.Atoi(parts[1])
-		if err != nil {
+		if err != nil || cost < 0 {
 			return nil, fmt.Errorf("invalid cost: %s", parts[1])
 		}
 
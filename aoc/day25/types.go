@@ -2,6 +2,7 @@ package day25
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -47,6 +48,28 @@ func (g Graph) AddDirectedEdge(from, to Node, cost int) {
 	g[from][to] = cost
 }
 
+// Format writes the graph in the input format accepted by ParseGraph, one
+// "A-B:10" line per tunnel. Each bidirectional tunnel is written once and the
+// lines are sorted so the output is deterministic.
+func (g Graph) Format() string {
+	var lines []string
+	for from, edges := range g {
+		for to, cost := range edges {
+			if from <= to {
+				lines = append(lines, Edge{From: from, To: to, Cost: cost}.String())
+			}
+		}
+	}
+	sort.Strings(lines)
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
 // Neighbors returns all adjacent nodes
 func (g Graph) Neighbors(node Node) []Node {
 	neighbors := make([]Node, 0, len(g[node]))
@@ -0,0 +1,189 @@
+package day3
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInfeasible is returned when no choice of batteries satisfies the constraints
+var ErrInfeasible = errors.New("no selection satisfies the constraints")
+
+// Constraints restrict which batteries SelectConstrained may choose together.
+// The zero value imposes nothing.
+type Constraints struct {
+	// MinGap is the smallest allowed distance between chosen positions
+	// (0 or 1: neighbours may both be chosen; 2: at least one battery between).
+	MinGap int
+
+	// Window splits the bank into consecutive blocks of Window batteries
+	// (positions 0..Window-1, Window..2·Window-1, ...), from each of which at
+	// most PerWindow may be chosen. Window 0 means no cap.
+	Window, PerWindow int
+
+	// DistinctDigits forbids choosing the same digit twice.
+	DistinctDigits bool
+}
+
+// IsZero reports whether the constraints impose nothing
+func (c Constraints) IsZero() bool {
+	return c.MinGap <= 1 && c.Window <= 0 && !c.DistinctDigits
+}
+
+// SelectConstrained chooses k batteries, keeping their order, that make the
+// largest (or smallest) k-digit number allowed by the constraints. Without
+// constraints it is Select.
+//
+// Algorithm: feasibility DP plus greedy digit choice
+// All candidates have k digits, so the best number is the lexicographically
+// best digit string, and it can be built one digit at a time: take the best
+// digit whose position still leaves a way to choose the rest. Whether the rest
+// can be chosen depends only on the state after the pick:
+//
+//	(next allowed position, digits left, digits used, picks in current window)
+//
+// so feasible() memoises that question. Among positions holding the best digit
+// the earliest is taken; it leaves every option a later one would, and more.
+//
+// Time complexity: O(n · k · masks · caps) states, O(n · k) checks to build the
+// answer, where masks is 1024 with DistinctDigits (else 1) and caps is
+// min(PerWindow, k)+1 with a window (else 1).
+func SelectConstrained(bank string, k int, objective Objective, c Constraints) (Selection, error) {
+	if c.IsZero() {
+		return Select(bank, k, objective)
+	}
+	if k < 0 || k > len(bank) {
+		return Selection{}, fmt.Errorf("choosing %d of %d: %w", k, len(bank), ErrBankTooShort)
+	}
+
+	s := newConstrainedSearch(bank, k, c)
+	if !s.feasible(0, k, 0, 0) {
+		return Selection{}, fmt.Errorf("choosing %d of %d with %+v: %w", k, len(bank), c, ErrInfeasible)
+	}
+
+	better := func(d, than int) bool { return d > than }
+	if objective == Minimize {
+		better = func(d, than int) bool { return d < than }
+	}
+
+	sel := Selection{Indices: make([]int, 0, k)}
+	digits := make([]byte, 0, k)
+	pos, mask, used := 0, 0, 0
+	for r := k; r > 0; r-- {
+		bestJ, bestDigit := -1, -1
+		for j := pos; j < len(bank); j++ {
+			d := int(bank[j] - '0')
+			if bestJ >= 0 && !better(d, bestDigit) {
+				continue
+			}
+			usedAtJ := s.carry(pos, j, used)
+			if !s.canPick(j, d, mask, usedAtJ) {
+				continue
+			}
+			next, nextUsed := s.afterPick(j, usedAtJ)
+			if s.feasible(next, r-1, s.addDigit(mask, d), nextUsed) {
+				bestJ, bestDigit = j, d
+			}
+		}
+
+		sel.Indices = append(sel.Indices, bestJ)
+		digits = append(digits, bank[bestJ])
+		pos, used = s.afterPick(bestJ, s.carry(pos, bestJ, used))
+		mask = s.addDigit(mask, bestDigit)
+	}
+	sel.Digits = string(digits)
+	return sel, nil
+}
+
+// constrainedSearch holds the memo for one SelectConstrained call
+type constrainedSearch struct {
+	bank  string
+	k     int
+	c     Constraints
+	gap   int
+	masks int    // 1024 when tracking digits, else 1
+	caps  int    // distinct values of "picks in the current window"
+	memo  []int8 // 0 unknown, 1 feasible, -1 infeasible
+}
+
+func newConstrainedSearch(bank string, k int, c Constraints) *constrainedSearch {
+	s := &constrainedSearch{bank: bank, k: k, c: c, gap: max(c.MinGap, 1), masks: 1, caps: 1}
+	if c.DistinctDigits {
+		s.masks = 1 << 10
+	}
+	if c.Window > 0 {
+		s.caps = min(max(c.PerWindow, 0), k) + 1
+	}
+	s.memo = make([]int8, (len(bank)+1)*(k+1)*s.masks*s.caps)
+	return s
+}
+
+// window returns the block a position falls in (always 0 without a cap)
+func (s *constrainedSearch) window(i int) int {
+	if s.c.Window <= 0 {
+		return 0
+	}
+	return i / s.c.Window
+}
+
+// carry returns the picks counted against position to's window, given used
+// picks in position from's window: moving into a new window starts afresh
+func (s *constrainedSearch) carry(from, to, used int) int {
+	if s.window(from) != s.window(to) {
+		return 0
+	}
+	return used
+}
+
+func (s *constrainedSearch) canPick(i, digit, mask, used int) bool {
+	if s.c.DistinctDigits && mask&(1<<digit) != 0 {
+		return false
+	}
+	return s.c.Window <= 0 || used < s.c.PerWindow
+}
+
+// afterPick returns the next allowed position after picking i and the picks
+// counted against that position's window
+func (s *constrainedSearch) afterPick(i, used int) (next, nextUsed int) {
+	next = i + s.gap
+	if s.c.Window <= 0 {
+		return next, 0
+	}
+	return next, s.carry(i, next, used+1)
+}
+
+func (s *constrainedSearch) addDigit(mask, digit int) int {
+	if !s.c.DistinctDigits {
+		return 0
+	}
+	return mask | 1<<digit
+}
+
+// feasible reports whether r more batteries can be chosen from position i onwards
+func (s *constrainedSearch) feasible(i, r, mask, used int) bool {
+	if r == 0 {
+		return true
+	}
+	if i >= len(s.bank) || len(s.bank)-i < r {
+		return false
+	}
+
+	key := ((i*(s.k+1)+r)*s.masks+mask)*s.caps + used
+	if s.memo[key] != 0 {
+		return s.memo[key] > 0
+	}
+
+	ok := s.feasible(i+1, r, mask, s.carry(i, i+1, used))
+	if !ok {
+		d := int(s.bank[i] - '0')
+		if s.canPick(i, d, mask, used) {
+			next, nextUsed := s.afterPick(i, used)
+			ok = s.feasible(next, r-1, s.addDigit(mask, d), nextUsed)
+		}
+	}
+
+	s.memo[key] = -1
+	if ok {
+		s.memo[key] = 1
+	}
+	return ok
+}
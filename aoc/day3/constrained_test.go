@@ -0,0 +1,128 @@
+package day3
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// allowed checks a set of chosen indices against the constraints directly
+func allowed(bank string, indices []int, c Constraints) bool {
+	seen := map[byte]bool{}
+	perWindow := map[int]int{}
+	for j, i := range indices {
+		if j > 0 && i-indices[j-1] < max(c.MinGap, 1) {
+			return false
+		}
+		if c.DistinctDigits && seen[bank[i]] {
+			return false
+		}
+		seen[bank[i]] = true
+		if c.Window > 0 {
+			perWindow[i/c.Window]++
+			if perWindow[i/c.Window] > c.PerWindow {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// bruteForceConstrained tries every subsequence of length k that the
+// constraints allow and returns the best digit string ("" if none is allowed)
+func bruteForceConstrained(bank string, k int, objective Objective, c Constraints) string {
+	best := ""
+	var choose func(start int, picked []int)
+	choose = func(start int, picked []int) {
+		if !allowed(bank, picked, c) {
+			return
+		}
+		if len(picked) == k {
+			digits := make([]byte, k)
+			for j, i := range picked {
+				digits[j] = bank[i]
+			}
+			s := string(digits)
+			if best == "" || (objective == Maximize && s > best) || (objective == Minimize && s < best) {
+				best = s
+			}
+			return
+		}
+		for i := start; i < len(bank); i++ {
+			choose(i+1, append(picked, i))
+		}
+	}
+	choose(0, nil)
+	return best
+}
+
+func TestSelectConstrainedMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 3000; trial++ {
+		digits := make([]byte, 1+r.Intn(11))
+		for i := range digits {
+			digits[i] = byte('0' + r.Intn(10))
+		}
+		bank := string(digits)
+		k := 1 + r.Intn(min(len(bank), 5))
+
+		c := Constraints{MinGap: r.Intn(4), DistinctDigits: r.Intn(2) == 0}
+		if r.Intn(2) == 0 {
+			c.Window, c.PerWindow = 1+r.Intn(4), r.Intn(3)
+		}
+		objective := Objective(r.Intn(2))
+
+		want := bruteForceConstrained(bank, k, objective, c)
+		got, err := SelectConstrained(bank, k, objective, c)
+		if want == "" {
+			if !errors.Is(err, ErrInfeasible) {
+				t.Fatalf("%q k=%d %+v: got %v, %v; want ErrInfeasible", bank, k, c, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q k=%d %+v: %v", bank, k, c, err)
+		}
+		if got.Digits != want || !allowed(bank, got.Indices, c) {
+			t.Fatalf("%q k=%d %+v objective %d: got %s at %v, want %s", bank, k, c, objective, got.Digits, got.Indices, want)
+		}
+	}
+}
+
+func TestSelectConstrainedWithoutConstraintsIsSelect(t *testing.T) {
+	for _, bank := range exampleBanks {
+		got, err := SelectConstrained(bank, 12, Maximize, Constraints{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := Select(bank, 12, Maximize)
+		if got.Digits != want.Digits {
+			t.Errorf("%s: got %s, want %s", bank, got.Digits, want.Digits)
+		}
+	}
+}
+
+func TestSelectConstrainedExamples(t *testing.T) {
+	for _, tc := range []struct {
+		bank string
+		k    int
+		c    Constraints
+		want string
+	}{
+		{"987654321111111", 3, Constraints{MinGap: 2}, "975"},
+		{"987654321111111", 3, Constraints{Window: 5, PerWindow: 1}, "941"},
+		{"811111111111119", 3, Constraints{DistinctDigits: true}, "819"},
+		{"234234234234278", 5, Constraints{DistinctDigits: true, MinGap: 3}, ""},
+	} {
+		got, err := SelectConstrained(tc.bank, tc.k, Maximize, tc.c)
+		if tc.want == "" {
+			if !errors.Is(err, ErrInfeasible) {
+				t.Errorf("%s %+v: got %v, %v; want ErrInfeasible", tc.bank, tc.c, got, err)
+			}
+			continue
+		}
+		if err != nil || got.Digits != tc.want {
+			t.Errorf("%s %+v: got %s, %v; want %s", tc.bank, tc.c, got.Digits, err, tc.want)
+		}
+	}
+}
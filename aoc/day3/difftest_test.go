@@ -0,0 +1,71 @@
+package day3
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"adv2025/aoc/difftest"
+)
+
+// bruteForceJoltage tries every way of keeping k batteries in order and
+// returns the largest resulting number. It is exponential and only meant as a
+// reference for small banks.
+func bruteForceJoltage(bank string, k int) int {
+	best := 0
+	var choose func(start, picked, value int)
+	choose = func(start, picked, value int) {
+		if picked == k {
+			best = max(best, value)
+			return
+		}
+		for i := start; i <= len(bank)-(k-picked); i++ {
+			choose(i+1, picked+1, value*10+int(bank[i]-'0'))
+		}
+	}
+	if len(bank) >= k {
+		choose(0, 0, 0)
+	}
+	return best
+}
+
+func bankProperty(name string, minLen, maxLen int, reference, candidate func(string) int) difftest.Property[string] {
+	return difftest.Property[string]{
+		Name:      name,
+		Reference: func(bank string) (int, error) { return reference(bank), nil },
+		Candidate: func(bank string) (int, error) { return candidate(bank), nil },
+		Generate: func(r *rand.Rand) string {
+			digits := make([]byte, minLen+r.Intn(maxLen-minLen+1))
+			for i := range digits {
+				digits[i] = byte('1' + r.Intn(9))
+			}
+			return string(digits)
+		},
+		Shrink: func(bank string) []string {
+			var out []string
+			for _, digits := range difftest.ShrinkSlice([]byte(bank), func(b byte) []byte {
+				if b > '1' {
+					return []byte{'1', b - 1}
+				}
+				return nil
+			}) {
+				out = append(out, string(digits))
+			}
+			return out
+		},
+		Encode: func(bank string) string { return bank + "\n" },
+		Decode: func(s string) (string, error) { return strings.TrimSpace(s), nil },
+	}
+}
+
+func TestMaxJoltageMatchesBruteForce(t *testing.T) {
+	difftest.Check(t, bankProperty("joltage-2", 2, 20,
+		func(bank string) int { return bruteForceJoltage(bank, 2) },
+		func(bank string) int { return maxJoltage(bank, 2) }))
+}
+
+func TestMaxJoltage12MatchesBruteForce(t *testing.T) {
+	difftest.Check(t, bankProperty("joltage-12", 12, 16,
+		func(bank string) int { return bruteForceJoltage(bank, 12) },
+		func(bank string) int { return maxJoltage(bank, 12) }))
+}
@@ -0,0 +1,122 @@
+package day3
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Objective chooses whether Select keeps the largest or the smallest number
+type Objective int
+
+const (
+	Maximize Objective = iota
+	Minimize
+)
+
+// ErrBankTooShort is returned when a bank has fewer batteries than must be chosen
+var ErrBankTooShort = errors.New("bank has fewer batteries than requested")
+
+// Selection is the batteries chosen from a bank, in bank order
+type Selection struct {
+	Indices []int  // positions in the bank, increasing
+	Digits  string // the chosen digits, most significant first
+}
+
+// Int returns the selection as an int. ok is false when it has more than 18
+// digits, where it may not fit; use BigInt then.
+func (s Selection) Int() (n int, ok bool) {
+	if len(s.Digits) > 18 {
+		return 0, false
+	}
+	for i := 0; i < len(s.Digits); i++ {
+		n = n*10 + int(s.Digits[i]-'0')
+	}
+	return n, true
+}
+
+// BigInt returns the selection as an arbitrarily large number
+func (s Selection) BigInt() *big.Int {
+	n := new(big.Int)
+	if s.Digits != "" {
+		n.SetString(s.Digits, 10)
+	}
+	return n
+}
+
+// Select chooses k batteries from the bank, keeping their order, so that the
+// resulting k-digit number is as large (or as small) as possible.
+//
+// Algorithm: monotonic stack, O(n) for any k
+// We may drop n-k batteries. Walking the bank left to right, a battery on the
+// stack that is smaller than the current one (larger, when minimising) should
+// be dropped while drops remain: keeping it would put a worse digit in a more
+// significant position. The stack therefore stays non-increasing, and its
+// first k entries are the answer. Each battery is pushed and popped at most
+// once.
+//
+// Example: "818181911112111", k=12, maximise
+// - Three drops pop the 1s in front of the second 8, the third 8 and the 9
+// - The stack is then 8 8 8 9 followed by 11112111 → 888911112111
+//
+// Equal digits are never popped, so earlier batteries win ties.
+func Select(bank string, k int, objective Objective) (Selection, error) {
+	if k < 0 || k > len(bank) {
+		return Selection{}, fmt.Errorf("choosing %d of %d: %w", k, len(bank), ErrBankTooShort)
+	}
+
+	worse := func(top, next byte) bool { return top < next }
+	if objective == Minimize {
+		worse = func(top, next byte) bool { return top > next }
+	}
+
+	drops := len(bank) - k
+	stack := make([]int, 0, len(bank))
+	for i := 0; i < len(bank); i++ {
+		for drops > 0 && len(stack) > 0 && worse(bank[stack[len(stack)-1]], bank[i]) {
+			stack = stack[:len(stack)-1]
+			drops--
+		}
+		stack = append(stack, i)
+	}
+
+	indices := stack[:k]
+	digits := make([]byte, k)
+	for j, i := range indices {
+		digits[j] = bank[i]
+	}
+	return Selection{Indices: indices, Digits: string(digits)}, nil
+}
+
+// maxJoltage returns the largest k-digit joltage of a bank as an int, or 0 when
+// the bank is too short (k must be at most 18)
+func maxJoltage(bank string, k int) int {
+	s, err := Select(bank, k, Maximize)
+	if err != nil {
+		return 0
+	}
+	n, _ := s.Int()
+	return n
+}
+
+// totalJoltage sums the largest k-digit joltage of every bank, in a big.Int so
+// that any k works
+func totalJoltage(banks []string, k int) *big.Int {
+	total := new(big.Int)
+	for _, bank := range banks {
+		s, err := Select(bank, k, Maximize)
+		if err != nil {
+			continue // too short to contribute, as in the puzzle
+		}
+		total.Add(total, s.BigInt())
+	}
+	return total
+}
+
+// bigToInt converts a total back to the runner's int, failing if it does not fit
+func bigToInt(n *big.Int) (int, error) {
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("total joltage %s does not fit in an int", n)
+	}
+	return int(n.Int64()), nil
+}
@@ -0,0 +1,118 @@
+package day3
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var exampleBanks = []string{"987654321111111", "811111111111119", "234234234234278", "818181911112111"}
+
+func TestExample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(exampleBanks, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{357, 3121910778619} {
+		got, err := Parts[i](path)
+		if err != nil {
+			t.Fatalf("part %d: %v", i+1, err)
+		}
+		if got != want {
+			t.Errorf("part %d = %d, want %d", i+1, got, want)
+		}
+	}
+}
+
+func TestSelectIndices(t *testing.T) {
+	s, err := Select("818181911112111", 12, Maximize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Digits != "888911112111" {
+		t.Errorf("digits = %s, want 888911112111", s.Digits)
+	}
+	if want := []int{0, 2, 4, 6, 7, 8, 9, 10, 11, 12, 13, 14}; !slices.Equal(s.Indices, want) {
+		t.Errorf("indices = %v, want %v", s.Indices, want)
+	}
+
+	if _, err := Select("123", 4, Maximize); !errors.Is(err, ErrBankTooShort) {
+		t.Errorf("choosing 4 of 3: got %v, want ErrBankTooShort", err)
+	}
+}
+
+// bruteForceSelect tries every subsequence of length k and keeps the best by
+// digit string (all candidates have k digits, so that is numeric order)
+func bruteForceSelect(bank string, k int, objective Objective) string {
+	best := ""
+	var choose func(start int, picked []byte)
+	choose = func(start int, picked []byte) {
+		if len(picked) == k {
+			s := string(picked)
+			if best == "" || (objective == Maximize && s > best) || (objective == Minimize && s < best) {
+				best = s
+			}
+			return
+		}
+		for i := start; i <= len(bank)-(k-len(picked)); i++ {
+			choose(i+1, append(picked, bank[i]))
+		}
+	}
+	choose(0, nil)
+	return best
+}
+
+func TestSelectMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 2000; trial++ {
+		digits := make([]byte, 1+r.Intn(12))
+		for i := range digits {
+			digits[i] = byte('0' + r.Intn(10))
+		}
+		bank := string(digits)
+		k := r.Intn(len(bank) + 1)
+
+		for _, objective := range []Objective{Maximize, Minimize} {
+			s, err := Select(bank, k, objective)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := bruteForceSelect(bank, k, objective); s.Digits != want {
+				t.Fatalf("Select(%q, %d, %v) = %s, want %s", bank, k, objective, s.Digits, want)
+			}
+			for j, i := range s.Indices {
+				if bank[i] != s.Digits[j] || (j > 0 && i <= s.Indices[j-1]) {
+					t.Fatalf("Select(%q, %d, %v): indices %v do not spell %s", bank, k, objective, s.Indices, s.Digits)
+				}
+			}
+		}
+	}
+}
+
+func TestSelectBeyondInt(t *testing.T) {
+	bank := strings.Repeat("9", 25) + strings.Repeat("1", 10)
+	s, err := Select(bank, 30, Maximize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Int(); ok {
+		t.Error("a 30-digit selection should not claim to fit in an int")
+	}
+	want, _ := new(big.Int).SetString(strings.Repeat("9", 25)+"11111", 10)
+	if s.BigInt().Cmp(want) != 0 {
+		t.Errorf("BigInt = %s, want %s", s.BigInt(), want)
+	}
+
+	total := totalJoltage([]string{bank, bank}, 30)
+	if total.Cmp(new(big.Int).Mul(want, big.NewInt(2))) != 0 {
+		t.Errorf("total = %s", total)
+	}
+	if _, err := bigToInt(total); err == nil {
+		t.Error("bigToInt should reject a 31-digit total")
+	}
+}
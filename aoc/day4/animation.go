@@ -0,0 +1,203 @@
+package day4
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"strings"
+	"time"
+)
+
+// Colours used for the cells that are not removed rolls.
+var (
+	emptyColour = color.RGBA{255, 255, 255, 255}
+	rollColour  = color.RGBA{90, 90, 90, 255}
+)
+
+// waveSymbols label removed rolls in ASCII frames; waves past the last symbol
+// share '+'.
+const waveSymbols = "123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// WaveColour returns the colour of rolls removed in the given wave (1-based)
+// out of waves. Colours run around the hue circle from red for the first wave
+// to violet for the last, so early and late erosion are easy to tell apart.
+func WaveColour(wave, waves int) color.RGBA {
+	hue := 0.0
+	if waves > 1 {
+		hue = 280 * float64(wave-1) / float64(waves-1)
+	}
+	return hsv(hue, 0.85, 0.95)
+}
+
+// hsv converts a hue in degrees with saturation and value in [0, 1] to RGB.
+func hsv(h, s, v float64) color.RGBA {
+	c := v * s
+	hp := h / 60
+	x := c * (1 - abs(mod2(hp)-1))
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g = c, x
+	case hp < 2:
+		r, g = x, c
+	case hp < 3:
+		g, b = c, x
+	case hp < 4:
+		g, b = x, c
+	case hp < 5:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := v - c
+	return color.RGBA{uint8(255 * (r + m)), uint8(255 * (g + m)), uint8(255 * (b + m)), 255}
+}
+
+func mod2(x float64) float64 {
+	return x - 2*float64(int(x/2))
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// removedBy reports whether the roll at (row, col) is gone after the given
+// wave, and which wave removed it.
+func (r Removal) removedBy(row, col, wave int) (int, bool) {
+	w := r.Wave[row][col]
+	return w, w > 0 && w <= wave
+}
+
+// Frame draws the grid as ASCII after the given wave (0 is the starting grid).
+// Rolls still standing are '@', empty cells '.', and a removed roll shows the
+// symbol of the wave that removed it: 1-9, then a-z and A-Z, then '+'. With
+// colour, removed rolls are also painted in their WaveColour using 24-bit ANSI
+// escapes.
+func (r Removal) Frame(wave int, colour bool) string {
+	var sb strings.Builder
+	for row, line := range r.Grid {
+		for col := 0; col < len(line); col++ {
+			w, removed := r.removedBy(row, col, wave)
+			if !removed {
+				sb.WriteByte(line[col])
+				continue
+			}
+			symbol := byte('+')
+			if w <= len(waveSymbols) {
+				symbol = waveSymbols[w-1]
+			}
+			if colour {
+				c := WaveColour(w, len(r.Waves))
+				fmt.Fprintf(&sb, "\033[38;2;%d;%d;%dm%c\033[0m", c.R, c.G, c.B, symbol)
+			} else {
+				sb.WriteByte(symbol)
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// caption describes the state after the given wave.
+func (r Removal) caption(wave int) string {
+	if wave == 0 {
+		return fmt.Sprintf("Start: %d waves will remove %d rolls", len(r.Waves), r.Total)
+	}
+	removed := 0
+	for _, n := range r.Waves[:wave] {
+		removed += n
+	}
+	return fmt.Sprintf("Wave %d/%d: removed %d (%d so far)", wave, len(r.Waves), r.Waves[wave-1], removed)
+}
+
+// WriteFrames writes every frame, starting grid first, each preceded by its
+// caption and followed by a blank line.
+func (r Removal) WriteFrames(w io.Writer, colour bool) error {
+	for wave := 0; wave <= len(r.Waves); wave++ {
+		if _, err := fmt.Fprintf(w, "%s\n%s\n", r.caption(wave), r.Frame(wave, colour)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Animate plays the frames in a terminal, clearing it between frames.
+func (r Removal) Animate(w io.Writer, delay time.Duration, colour bool) error {
+	for wave := 0; wave <= len(r.Waves); wave++ {
+		if _, err := fmt.Fprintf(w, "\033[H\033[2J%s\n%s", r.caption(wave), r.Frame(wave, colour)); err != nil {
+			return err
+		}
+		time.Sleep(delay)
+	}
+	return nil
+}
+
+// Image draws the grid after the given wave with every cell a scale×scale
+// square: white for empty, grey for standing rolls and WaveColour for rolls
+// already removed.
+func (r Removal) Image(wave, scale int) *image.RGBA {
+	height := len(r.Grid)
+	width := 0
+	if height > 0 {
+		width = len(r.Grid[0])
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width*scale, height*scale))
+	draw.Draw(img, img.Bounds(), &image.Uniform{emptyColour}, image.Point{}, draw.Src)
+
+	for row, line := range r.Grid {
+		for col := 0; col < len(line); col++ {
+			var c color.Color
+			if w, removed := r.removedBy(row, col, wave); removed {
+				c = WaveColour(w, len(r.Waves))
+			} else if line[col] == '@' {
+				c = rollColour
+			} else {
+				continue
+			}
+			cell := image.Rect(col*scale, row*scale, (col+1)*scale, (row+1)*scale)
+			draw.Draw(img, cell, &image.Uniform{c}, image.Point{}, draw.Src)
+		}
+	}
+	return img
+}
+
+// Palette returns the colours every frame is drawn with: empty, roll, then one
+// per wave. GIF palettes hold at most 256 colours, so past 254 waves the wave
+// colours are sampled evenly and nearby waves share a colour.
+func (r Removal) Palette() color.Palette {
+	palette := color.Palette{emptyColour, rollColour}
+	waves := len(r.Waves)
+	slots := min(waves, 256-len(palette))
+	for i := 0; i < slots; i++ {
+		wave := 1
+		if slots > 1 {
+			wave = 1 + i*(waves-1)/(slots-1)
+		}
+		palette = append(palette, WaveColour(wave, waves))
+	}
+	return palette
+}
+
+// GIF builds an animation with one frame per wave after the starting grid.
+// delay is in hundredths of a second, as image/gif expects; the last frame is
+// held four times as long so the finished picture can be seen before looping.
+func (r Removal) GIF(scale, delay int) *gif.GIF {
+	palette := r.Palette()
+	anim := &gif.GIF{}
+	for wave := 0; wave <= len(r.Waves); wave++ {
+		img := r.Image(wave, scale)
+		frame := image.NewPaletted(img.Bounds(), palette)
+		draw.Draw(frame, frame.Bounds(), img, image.Point{}, draw.Src)
+
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	anim.Delay[len(anim.Delay)-1] *= 4
+	return anim
+}
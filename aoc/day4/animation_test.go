@@ -0,0 +1,85 @@
+package day4
+
+import (
+	"bytes"
+	"image/gif"
+	"strings"
+	"testing"
+)
+
+func TestFrames(t *testing.T) {
+	removal := RemoveAll(strings.Split(example, "\n"), WaveSynchronous)
+
+	if got := removal.Frame(0, false); got != example+"\n" {
+		t.Errorf("frame 0 is not the starting grid:\n%s", got)
+	}
+
+	// The puzzle marks the first row ..xx.xx@x. as accessible at the start
+	if got, want := strings.SplitN(removal.Frame(1, false), "\n", 2)[0], "..11.11@1."; got != want {
+		t.Errorf("frame 1 first row = %q, want %q", got, want)
+	}
+
+	last := removal.Frame(len(removal.Waves), false)
+	if got := strings.Count(last, "@"); got != strings.Count(example, "@")-removal.Total {
+		t.Errorf("last frame keeps %d rolls, want %d", got, strings.Count(example, "@")-removal.Total)
+	}
+	if !strings.Contains(last, "9") || strings.Contains(last, "+") {
+		t.Errorf("last frame should label all 9 waves:\n%s", last)
+	}
+
+	if coloured := removal.Frame(1, true); !strings.Contains(coloured, "\033[38;2;") {
+		t.Error("coloured frame has no colour escapes")
+	}
+
+	var buf bytes.Buffer
+	if err := removal.WriteFrames(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(buf.String(), "Wave "); got != len(removal.Waves) {
+		t.Errorf("wrote %d wave captions, want %d", got, len(removal.Waves))
+	}
+}
+
+func TestGIF(t *testing.T) {
+	removal := RemoveAll(strings.Split(example, "\n"), WaveSynchronous)
+	anim := removal.GIF(3, 10)
+
+	if len(anim.Image) != len(removal.Waves)+1 {
+		t.Fatalf("%d frames, want %d", len(anim.Image), len(removal.Waves)+1)
+	}
+	if anim.Delay[0] != 10 || anim.Delay[len(anim.Delay)-1] != 40 {
+		t.Errorf("delays %v, want 10s with the last held for 40", anim.Delay)
+	}
+
+	// Cell (0,2) is removed in wave 1: grey in the first frame, then coloured
+	first, second := anim.Image[0], anim.Image[1]
+	if got := first.At(7, 1); got != rollColour {
+		t.Errorf("standing roll drawn as %v", got)
+	}
+	if got, want := second.At(7, 1), WaveColour(1, len(removal.Waves)); got != want {
+		t.Errorf("wave 1 roll drawn as %v, want %v", got, want)
+	}
+	if got := second.At(1, 1); got != emptyColour {
+		t.Errorf("empty cell drawn as %v", got)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil || len(decoded.Image) != len(anim.Image) {
+		t.Errorf("round trip: %d frames, %v", len(decoded.Image), err)
+	}
+}
+
+func TestPaletteFitsGIF(t *testing.T) {
+	removal := Removal{Waves: make([]int, 1000)}
+	palette := removal.Palette()
+	if len(palette) != 256 {
+		t.Fatalf("palette has %d colours, want 256", len(palette))
+	}
+	if palette[2] != WaveColour(1, 1000) || palette[255] != WaveColour(1000, 1000) {
+		t.Error("palette should span the first to the last wave")
+	}
+}
@@ -0,0 +1,419 @@
+package day4
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// Neighbourhood is the set of cells a rule counts around each cell.
+//
+// Common choices in cellular automata:
+//
+//	Moore (8)      von Neumann (4)
+//	  # # #           . # .
+//	  # X #           # X #
+//	  # # #           . # .
+//
+// Offsets are {rowOffset, colOffset} pairs relative to the cell. With Wrap the
+// grid is a torus: stepping off one edge comes back on the opposite one.
+// Without it, cells outside the grid are simply not counted.
+type Neighbourhood struct {
+	Offsets [][2]int
+	Wrap    bool
+}
+
+// Moore returns the 8 surrounding cells (day 4's neighbourhood).
+func Moore() Neighbourhood {
+	return Neighbourhood{Offsets: [][2]int{
+		{-1, -1}, {-1, 0}, {-1, 1},
+		{0, -1}, {0, 1},
+		{1, -1}, {1, 0}, {1, 1},
+	}}
+}
+
+// VonNeumann returns the 4 orthogonally adjacent cells.
+func VonNeumann() Neighbourhood {
+	return Neighbourhood{Offsets: [][2]int{{-1, 0}, {0, -1}, {0, 1}, {1, 0}}}
+}
+
+// Comparison is how a rule compares its neighbour count with its threshold.
+type Comparison int
+
+const (
+	Less Comparison = iota
+	LessOrEqual
+	Equal
+	NotEqual
+	GreaterOrEqual
+	Greater
+)
+
+func (c Comparison) holds(count, threshold int) bool {
+	switch c {
+	case Less:
+		return count < threshold
+	case LessOrEqual:
+		return count <= threshold
+	case Equal:
+		return count == threshold
+	case NotEqual:
+		return count != threshold
+	case GreaterOrEqual:
+		return count >= threshold
+	default:
+		return count > threshold
+	}
+}
+
+// Rule turns a cell in state From into state To when the number of neighbours
+// in state Counts compares with Threshold as Compare says.
+//
+// Day 4's rule: a roll with fewer than 4 neighbouring rolls is removed
+//
+//	Rule{From: '@', To: '.', Counts: '@', Compare: Less, Threshold: 4}
+type Rule struct {
+	From, To  byte
+	Counts    byte
+	Compare   Comparison
+	Threshold int
+}
+
+// UpdatePolicy decides which generation of the grid a rule sees.
+type UpdatePolicy int
+
+const (
+	// Synchronous computes every cell from the previous generation, then
+	// updates them all at once (Conway's Game of Life, day 4's waves).
+	Synchronous UpdatePolicy = iota
+
+	// Sequential updates cells in place in reading order, so a cell sees the
+	// changes already made earlier in the same step (day 4's ASAP removal).
+	Sequential
+)
+
+// Config fully describes an automaton.
+type Config struct {
+	Neighbourhood Neighbourhood
+	Rules         []Rule // the first rule that applies to a cell wins
+	Update        UpdatePolicy
+
+	// DetectCycles makes Run remember every generation so it can stop when
+	// one repeats. It costs a copy of the grid per step.
+	DetectCycles bool
+
+	// OnChange, if set, is called for every cell a step changes.
+	OnChange func(step, row, col int, from, to byte)
+}
+
+// Automaton is a grid of single-byte cell states evolving under a Config.
+//
+// Only cells that can change are evaluated: a cell's next state depends on
+// itself and its neighbours, so after the first step only the cells around
+// the previous step's changes need another look. A roll only becomes
+// accessible when a neighbour is removed, so day 4 rechecks O(cells) in total.
+//
+// Each cell's neighbour counts are kept up to date rather than recounted: a
+// change adjusts the counts of the cells around it, so evaluating a cell costs
+// a comparison per rule instead of a walk over its neighbourhood.
+type Automaton struct {
+	cfg           Config
+	width, height int
+	cells         []byte
+
+	inverse [][2]int // offsets of the cells that count a given cell as a neighbour
+	counted []byte   // the states the rules count, without repeats
+	counts  [][]int  // counts[k][i]: neighbours of cell i in state counted[k]
+	ruleFor []int    // ruleFor[r]: the index in counted of rule r's Counts
+	dirty   []int    // cells to evaluate in the next step (nil means all)
+	step    int
+
+	// Steps that last queued each cell, to avoid duplicates: mark for the
+	// current (or, when synchronous, next) step, markNext for sequential sweeps.
+	mark, markNext []int
+}
+
+// NewAutomaton creates an automaton from grid rows. Short rows are padded with
+// '.' to the width of the longest.
+func NewAutomaton(grid []string, cfg Config) *Automaton {
+	a := &Automaton{cfg: cfg, height: len(grid)}
+	for _, line := range grid {
+		a.width = max(a.width, len(line))
+	}
+	a.cells = make([]byte, a.width*a.height)
+	for row := 0; row < a.height; row++ {
+		for col := 0; col < a.width; col++ {
+			a.cells[row*a.width+col] = '.'
+			if col < len(grid[row]) {
+				a.cells[row*a.width+col] = grid[row][col]
+			}
+		}
+	}
+
+	a.inverse = make([][2]int, len(cfg.Neighbourhood.Offsets))
+	for i, o := range cfg.Neighbourhood.Offsets {
+		a.inverse[i] = [2]int{-o[0], -o[1]}
+	}
+	a.ruleFor = make([]int, len(cfg.Rules))
+	for r, rule := range cfg.Rules {
+		k := slices.Index(a.counted, rule.Counts)
+		if k < 0 {
+			k = len(a.counted)
+			a.counted = append(a.counted, rule.Counts)
+		}
+		a.ruleFor[r] = k
+	}
+	a.counts = make([][]int, len(a.counted))
+	for k, state := range a.counted {
+		a.counts[k] = make([]int, len(a.cells))
+		for i := range a.cells {
+			for _, o := range cfg.Neighbourhood.Offsets {
+				if n := a.neighbour(i, o); n >= 0 && a.cells[n] == state {
+					a.counts[k][i]++
+				}
+			}
+		}
+	}
+
+	a.mark = make([]int, len(a.cells))
+	a.markNext = make([]int, len(a.cells))
+	return a
+}
+
+// neighbour returns the index of the cell at offset o from cell i, or -1 if
+// it is off the grid.
+func (a *Automaton) neighbour(i int, o [2]int) int {
+	r, c := i/a.width+o[0], i%a.width+o[1]
+	if a.cfg.Neighbourhood.Wrap {
+		r = ((r % a.height) + a.height) % a.height
+		c = ((c % a.width) + a.width) % a.width
+	} else if r < 0 || r >= a.height || c < 0 || c >= a.width {
+		return -1
+	}
+	return r*a.width + c
+}
+
+// next returns the state cell i moves to, from the current counts.
+func (a *Automaton) next(i int) byte {
+	state := a.cells[i]
+	for r, rule := range a.cfg.Rules {
+		if rule.From == state && rule.Compare.holds(a.counts[a.ruleFor[r]][i], rule.Threshold) {
+			return rule.To
+		}
+	}
+	return state
+}
+
+// set puts cell i in state to and updates the counts of the cells that have
+// it as a neighbour. An offset that reaches the same cell twice (possible when
+// wrapping a small grid) counts twice, as it does in the initial counts.
+func (a *Automaton) set(i int, to byte) {
+	from := a.cells[i]
+	a.cells[i] = to
+	for k, state := range a.counted {
+		delta := 0
+		if from == state {
+			delta--
+		}
+		if to == state {
+			delta++
+		}
+		if delta == 0 {
+			continue
+		}
+		for _, o := range a.inverse {
+			if n := a.neighbour(i, o); n >= 0 {
+				a.counts[k][n] += delta
+			}
+		}
+	}
+}
+
+// affected calls fn with cell i and every cell that has i as a neighbour.
+func (a *Automaton) affected(i int, fn func(n int)) {
+	fn(i)
+	for _, o := range a.inverse {
+		if n := a.neighbour(i, o); n >= 0 {
+			fn(n)
+		}
+	}
+}
+
+// candidates returns the cells to evaluate this step, in reading order for
+// the first step and in the order they were queued afterwards.
+func (a *Automaton) candidates() []int {
+	if a.dirty != nil {
+		return a.dirty
+	}
+	all := make([]int, len(a.cells))
+	for i := range all {
+		all[i] = i
+	}
+	return all
+}
+
+// Step advances the automaton one generation and returns how many cells changed.
+func (a *Automaton) Step() int {
+	a.step++
+	if a.cfg.Update == Sequential {
+		return a.stepSequential()
+	}
+	return a.stepSynchronous()
+}
+
+func (a *Automaton) stepSynchronous() int {
+	type change struct {
+		i        int
+		from, to byte
+	}
+	var changes []change
+	for _, i := range a.candidates() {
+		if to := a.next(i); to != a.cells[i] {
+			changes = append(changes, change{i, a.cells[i], to})
+		}
+	}
+
+	a.dirty = []int{}
+	for _, c := range changes {
+		a.set(c.i, c.to)
+		a.notify(c.i, c.from, c.to)
+		a.affected(c.i, func(n int) {
+			if a.mark[n] != a.step {
+				a.mark[n] = a.step
+				a.dirty = append(a.dirty, n)
+			}
+		})
+	}
+	return len(changes)
+}
+
+// stepSequential sweeps in reading order with a min-heap of cell indices. A
+// change ahead of the sweep joins this step; one behind it waits for the next.
+func (a *Automaton) stepSequential() int {
+	var current indexHeap
+	for _, i := range a.candidates() {
+		a.mark[i] = a.step
+		current = append(current, i)
+	}
+	heap.Init(&current)
+
+	var next []int
+	changed := 0
+	for current.Len() > 0 {
+		i := heap.Pop(&current).(int)
+		from := a.cells[i]
+		to := a.next(i)
+		if to == from {
+			continue
+		}
+		a.set(i, to)
+		changed++
+		a.notify(i, from, to)
+		a.affected(i, func(n int) {
+			if n > i {
+				if a.mark[n] != a.step {
+					a.mark[n] = a.step
+					heap.Push(&current, n)
+				}
+			} else if a.markNext[n] != a.step {
+				a.markNext[n] = a.step
+				next = append(next, n)
+			}
+		})
+	}
+	a.dirty = next
+	if a.dirty == nil {
+		a.dirty = []int{}
+	}
+	return changed
+}
+
+// indexHeap is a min-heap of cell indices for container/heap.
+type indexHeap []int
+
+func (h indexHeap) Len() int           { return len(h) }
+func (h indexHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func (a *Automaton) notify(i int, from, to byte) {
+	if a.cfg.OnChange != nil {
+		a.cfg.OnChange(a.step, i/a.width, i%a.width, from, to)
+	}
+}
+
+// Result describes a Run.
+type Result struct {
+	Changed    []int // cells changed by each step that changed anything
+	FixedPoint bool  // stopped because a step changed nothing
+
+	// With DetectCycles, a run that revisits a generation stops there.
+	// CycleStart is the step whose generation came back and CycleLength the
+	// number of steps between the two (1 would be a fixed point, reported as
+	// FixedPoint instead). CycleLength is 0 if no cycle was found.
+	CycleStart, CycleLength int
+}
+
+// Total returns the number of cell changes over the whole run.
+func (r Result) Total() int {
+	total := 0
+	for _, n := range r.Changed {
+		total += n
+	}
+	return total
+}
+
+// Run steps the automaton until a step changes nothing, a generation repeats
+// (with DetectCycles) or maxSteps steps have changed something (0: no limit).
+// Without cycle detection or a limit, an automaton that never settles runs forever.
+func (a *Automaton) Run(maxSteps int) Result {
+	var res Result
+	var seen map[string]int
+	if a.cfg.DetectCycles {
+		seen = map[string]int{string(a.cells): a.step}
+	}
+
+	for maxSteps <= 0 || len(res.Changed) < maxSteps {
+		changed := a.Step()
+		if changed == 0 {
+			res.FixedPoint = true
+			return res
+		}
+		res.Changed = append(res.Changed, changed)
+
+		if seen != nil {
+			state := string(a.cells)
+			if first, ok := seen[state]; ok {
+				res.CycleStart, res.CycleLength = first, a.step-first
+				return res
+			}
+			seen[state] = a.step
+		}
+	}
+	return res
+}
+
+// Grid returns the current generation as rows.
+func (a *Automaton) Grid() []string {
+	rows := make([]string, a.height)
+	for row := range rows {
+		rows[row] = string(a.cells[row*a.width : (row+1)*a.width])
+	}
+	return rows
+}
+
+// Count returns how many cells are in the given state.
+func (a *Automaton) Count(state byte) int {
+	count := 0
+	for _, c := range a.cells {
+		if c == state {
+			count++
+		}
+	}
+	return count
+}
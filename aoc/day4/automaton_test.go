@@ -0,0 +1,288 @@
+package day4
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// life is Conway's Game of Life: '#' alive, '.' dead
+func life(wrap bool) Config {
+	n := Moore()
+	n.Wrap = wrap
+	return Config{
+		Neighbourhood: n,
+		Rules: []Rule{
+			{From: '#', To: '.', Counts: '#', Compare: Less, Threshold: 2},
+			{From: '#', To: '.', Counts: '#', Compare: Greater, Threshold: 3},
+			{From: '.', To: '#', Counts: '#', Compare: Equal, Threshold: 3},
+		},
+		DetectCycles: true,
+	}
+}
+
+// naiveStep evaluates every cell from scratch, the reference for the
+// incremental engine
+func toBytes(lines []string) [][]byte {
+	grid := make([][]byte, len(lines))
+	for i, line := range lines {
+		grid[i] = []byte(line)
+	}
+	return grid
+}
+
+func naiveStep(grid []string, cfg Config) ([]string, int) {
+	height, width := len(grid), len(grid[0])
+	at := func(g [][]byte, r, c int) (byte, bool) {
+		if cfg.Neighbourhood.Wrap {
+			r, c = (r%height+height)%height, (c%width+width)%width
+		} else if r < 0 || r >= height || c < 0 || c >= width {
+			return 0, false
+		}
+		return g[r][c], true
+	}
+	next := func(g [][]byte, r, c int) byte {
+		for _, rule := range cfg.Rules {
+			if g[r][c] != rule.From {
+				continue
+			}
+			count := 0
+			for _, o := range cfg.Neighbourhood.Offsets {
+				if s, ok := at(g, r+o[0], c+o[1]); ok && s == rule.Counts {
+					count++
+				}
+			}
+			if rule.Compare.holds(count, rule.Threshold) {
+				return rule.To
+			}
+		}
+		return g[r][c]
+	}
+
+	cur := toBytes(grid)
+	out := toBytes(grid)
+	changed := 0
+	for r := range cur {
+		for c := range cur[r] {
+			if cfg.Update == Sequential {
+				out[r][c] = next(out, r, c)
+			} else {
+				out[r][c] = next(cur, r, c)
+			}
+			if out[r][c] != cur[r][c] {
+				changed++
+			}
+		}
+	}
+	rows := make([]string, height)
+	for r := range out {
+		rows[r] = string(out[r])
+	}
+	return rows, changed
+}
+
+func TestLifeBlinkerCycles(t *testing.T) {
+	a := NewAutomaton([]string{
+		".....",
+		"..#..",
+		"..#..",
+		"..#..",
+		".....",
+	}, life(true))
+
+	res := a.Run(10)
+	if res.FixedPoint || res.CycleStart != 0 || res.CycleLength != 2 {
+		t.Errorf("blinker: got %+v, want a cycle of length 2 from step 0", res)
+	}
+	if got := a.Count('#'); got != 3 {
+		t.Errorf("blinker has %d live cells, want 3", got)
+	}
+}
+
+func TestLifeBlockIsFixedPoint(t *testing.T) {
+	grid := []string{"....", ".##.", ".##.", "...."}
+	a := NewAutomaton(grid, life(false))
+	res := a.Run(0)
+	if !res.FixedPoint || len(res.Changed) != 0 {
+		t.Errorf("block: got %+v, want an immediate fixed point", res)
+	}
+	if !reflect.DeepEqual(a.Grid(), grid) {
+		t.Errorf("block changed to %v", a.Grid())
+	}
+}
+
+func TestRunStopsAfterMaxSteps(t *testing.T) {
+	// A glider on a torus never settles and takes 4·size steps to come back
+	grid := []string{
+		".#......",
+		"..#.....",
+		"###.....",
+		"........",
+		"........",
+		"........",
+		"........",
+		"........",
+	}
+	cfg := life(true)
+	cfg.DetectCycles = false
+	res := NewAutomaton(grid, cfg).Run(5)
+	if len(res.Changed) != 5 || res.FixedPoint || res.CycleLength != 0 {
+		t.Errorf("got %+v, want 5 steps and no verdict", res)
+	}
+
+	res = NewAutomaton(grid, life(true)).Run(0)
+	if res.CycleStart != 0 || res.CycleLength != 32 {
+		t.Errorf("glider: got cycle %d from %d, want 32 from 0", res.CycleLength, res.CycleStart)
+	}
+}
+
+func TestNeighbourhoods(t *testing.T) {
+	// Corners count toward Moore but not von Neumann
+	grid := []string{"#.#", ".@.", "#.#"}
+	rule := []Rule{{From: '@', To: '.', Counts: '#', Compare: GreaterOrEqual, Threshold: 1}}
+
+	if got := NewAutomaton(grid, Config{Neighbourhood: Moore(), Rules: rule}).Step(); got != 1 {
+		t.Errorf("Moore: %d changes, want 1", got)
+	}
+	if got := NewAutomaton(grid, Config{Neighbourhood: VonNeumann(), Rules: rule}).Step(); got != 0 {
+		t.Errorf("von Neumann: %d changes, want 0", got)
+	}
+
+	// A knight's-move neighbourhood only sees (2,1) away
+	knight := Neighbourhood{Offsets: [][2]int{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}}
+	a := NewAutomaton([]string{"@..", "...", ".#."}, Config{Neighbourhood: knight, Rules: rule})
+	if got := a.Step(); got != 1 || a.Grid()[0] != "..." {
+		t.Errorf("knight: %d changes, grid %v", got, a.Grid())
+	}
+}
+
+func TestWrapAround(t *testing.T) {
+	// The roll on the right edge only has neighbours across the left edge
+	grid := []string{"#...@", "#....", "....."}
+	rule := []Rule{{From: '@', To: '.', Counts: '#', Compare: Equal, Threshold: 2}}
+
+	if got := NewAutomaton(grid, Config{Neighbourhood: Moore(), Rules: rule}).Step(); got != 0 {
+		t.Errorf("bounded: %d changes, want 0", got)
+	}
+	wrapped := Moore()
+	wrapped.Wrap = true
+	if got := NewAutomaton(grid, Config{Neighbourhood: wrapped, Rules: rule}).Step(); got != 1 {
+		t.Errorf("wrapped: %d changes, want 1", got)
+	}
+}
+
+func TestUpdatePolicies(t *testing.T) {
+	// A lit cell lights its right-hand neighbour: synchronously the light moves
+	// one cell per step, sequentially it runs along the whole row at once
+	spread := Neighbourhood{Offsets: [][2]int{{0, -1}}}
+	rule := []Rule{{From: '.', To: '#', Counts: '#', Compare: Equal, Threshold: 1}}
+
+	sync := NewAutomaton([]string{"#...."}, Config{Neighbourhood: spread, Rules: rule})
+	if res := sync.Run(0); !reflect.DeepEqual(res.Changed, []int{1, 1, 1, 1}) {
+		t.Errorf("synchronous: changed %v, want [1 1 1 1]", res.Changed)
+	}
+
+	seq := NewAutomaton([]string{"#...."}, Config{Neighbourhood: spread, Rules: rule, Update: Sequential})
+	if res := seq.Run(0); !reflect.DeepEqual(res.Changed, []int{4}) {
+		t.Errorf("sequential: changed %v, want [4]", res.Changed)
+	}
+}
+
+func TestOnChange(t *testing.T) {
+	type change struct {
+		step, row, col int
+		from, to       byte
+	}
+	var got []change
+	cfg := removeAccessible(Synchronous)
+	cfg.OnChange = func(step, row, col int, from, to byte) {
+		got = append(got, change{step, row, col, from, to})
+	}
+
+	NewAutomaton([]string{"@@", "@."}, cfg).Run(0)
+	want := []change{{1, 0, 0, '@', '.'}, {1, 0, 1, '@', '.'}, {1, 1, 0, '@', '.'}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes %v, want %v", got, want)
+	}
+}
+
+func TestAutomatonMatchesNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	states := []byte(".#@")
+	for trial := 0; trial < 300; trial++ {
+		rows, cols := 1+r.Intn(8), 1+r.Intn(8)
+		grid := make([]string, rows)
+		for i := range grid {
+			var sb strings.Builder
+			for j := 0; j < cols; j++ {
+				sb.WriteByte(states[r.Intn(len(states))])
+			}
+			grid[i] = sb.String()
+		}
+
+		cfg := Config{Update: UpdatePolicy(r.Intn(2))}
+		cfg.Neighbourhood = Moore()
+		if r.Intn(2) == 0 {
+			cfg.Neighbourhood = VonNeumann()
+		}
+		cfg.Neighbourhood.Wrap = r.Intn(2) == 0
+		for i := 1 + r.Intn(3); i > 0; i-- {
+			cfg.Rules = append(cfg.Rules, Rule{
+				From:      states[r.Intn(len(states))],
+				To:        states[r.Intn(len(states))],
+				Counts:    states[r.Intn(len(states))],
+				Compare:   Comparison(r.Intn(6)),
+				Threshold: r.Intn(5),
+			})
+		}
+
+		a := NewAutomaton(grid, cfg)
+		want := grid
+		for step := 1; step <= 10; step++ {
+			var changed int
+			want, changed = naiveStep(want, cfg)
+			if got := a.Step(); got != changed || !reflect.DeepEqual(a.Grid(), want) {
+				t.Fatalf("trial %d step %d with %+v:\ngot %d changes %v\nwant %d changes %v",
+					trial, step, cfg, got, a.Grid(), changed, want)
+			}
+		}
+	}
+}
+
+// TestEngineMatchesOriginal checks the day 4 configuration against the
+// original hand-written solution (reference_test.go), wave by wave
+func TestEngineMatchesOriginal(t *testing.T) {
+	r := rand.New(rand.NewSource(41))
+	for trial := 0; trial < 300; trial++ {
+		lines := randomGrid(r)
+
+		wantFirst := 0
+		for row := range lines {
+			for col := range lines[row] {
+				if lines[row][col] == '@' && isAccessible(lines, row, col) {
+					wantFirst++
+				}
+			}
+		}
+		if got := NewAutomaton(lines, removeAccessible(Synchronous)).Step(); got != wantFirst {
+			t.Fatalf("grid %v: first step removes %d, original counts %d", lines, got, wantFirst)
+		}
+
+		grid := toBytes(lines)
+		var want []int
+		for {
+			accessible := findAccessibleRolls(grid)
+			if len(accessible) == 0 {
+				break
+			}
+			for _, pos := range accessible {
+				grid[pos.row][pos.col] = '.'
+			}
+			want = append(want, len(accessible))
+		}
+		if got := NewAutomaton(lines, removeAccessible(Synchronous)).Run(0).Changed; !reflect.DeepEqual(got, want) {
+			t.Fatalf("grid %v: engine waves %v, original %v", lines, got, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package day4
+
+// The original hand-written day 4 solution, kept as the reference that the
+// Automaton and RemoveAll are checked against (see TestEngineMatchesOriginal).
+
+// isAccessible returns true if a roll at (row, col) has fewer than 4 adjacent rolls.
+//
+// Helper Function Pattern: Extract complex logic into named functions for:
+// - Readability: Function name documents intent
+// - Testability: Can test isAccessible() independently
+// - Reusability: Used by both Part1 (if needed elsewhere)
+// - Single Responsibility: Each function does one thing well
+//
+// Adjacency Checking: Common pattern in grid problems (Conway's Game of Life, etc.)
+func isAccessible(grid []string, row, col int) bool {
+	adjacentCount := 0
+
+	// Direction vectors: Mathematical approach to neighbor checking
+	// Each [2]int is [rowOffset, colOffset] relative to current position
+	// This is more maintainable than 8 separate if statements
+	//
+	// Layout visualization:
+	//   [-1,-1] [-1,0] [-1,1]    NW  N  NE
+	//   [ 0,-1]  [X,Y] [ 0,1]     W  @   E
+	//   [ 1,-1] [ 1,0] [ 1,1]    SW  S  SE
+	directions := [][2]int{
+		{-1, -1}, {-1, 0}, {-1, 1}, // top row
+		{0, -1}, {0, 1}, // left and right (skip center)
+		{1, -1}, {1, 0}, {1, 1}, // bottom row
+	}
+
+	// Check each of the 8 surrounding cells
+	for _, dir := range directions {
+		newRow := row + dir[0]
+		newCol := col + dir[1]
+
+		// Bounds checking: critical for grid problems to avoid panics
+		// Go will panic on out-of-bounds slice access, unlike some languages
+		// Order matters: check row bounds before accessing grid[newRow]
+		if newRow >= 0 && newRow < len(grid) &&
+			newCol >= 0 && newCol < len(grid[newRow]) &&
+			grid[newRow][newCol] == '@' {
+			adjacentCount++
+		}
+	}
+
+	// Problem constraint: accessible if FEWER than 4 adjacent (0-3 is accessible)
+	return adjacentCount < 4
+}
+
+// position represents a 2D coordinate in the grid.
+//
+// Struct Pattern: Use structs to group related data
+// - More readable than passing two separate ints
+// - Type safety: can't accidentally swap row/col
+// - Extensible: easy to add fields like "value" or "type" later
+//
+// Unexported (lowercase): This is an implementation detail
+// External packages don't need to know about our position type
+type position struct {
+	row, col int
+}
+
+// findAccessibleRolls returns positions of all accessible rolls in the grid.
+//
+// Part2 no longer rescans with this (see RemoveAll); it remains the simple
+// reference that the worklist and the Automaton are tested against.
+//
+// This function demonstrates:
+// - Separation of concerns: finding vs. removing are separate operations
+// - Collecting results in a slice for batch processing
+// - Working with mutable [][]byte grids
+func findAccessibleRolls(grid [][]byte) []position {
+	var accessible []position
+
+	// Same traversal pattern as Part1, but collecting positions instead of counting
+	for row := 0; row < len(grid); row++ {
+		for col := 0; col < len(grid[row]); col++ {
+			if grid[row][col] == '@' && isAccessibleMutable(grid, row, col) {
+				// Struct literal: position{row, col} creates position with named fields
+				accessible = append(accessible, position{row, col})
+			}
+		}
+	}
+
+	return accessible
+}
+
+// isAccessibleMutable checks if a roll is accessible in a mutable grid.
+//
+// Function Naming: "Mutable" suffix indicates this works with [][]byte
+// Part1's isAccessible() works with []string (immutable)
+// Both implement the same logic, but on different types
+//
+// Why duplicate instead of generics?
+// - Different types ([]string vs [][]byte) for different use cases
+// - Part1 doesn't need mutability (simpler, safer)
+// - Part2 needs mutability (efficiency)
+// - Small amount of duplication (< 20 lines) is acceptable in Go
+// - "A little copying is better than a little dependency" - Go proverb
+func isAccessibleMutable(grid [][]byte, row, col int) bool {
+	adjacentCount := 0
+
+	// Same direction vectors as Part1 - mathematical pattern for neighbors
+	directions := [][2]int{
+		{-1, -1}, {-1, 0}, {-1, 1}, // top row
+		{0, -1}, {0, 1}, // left and right
+		{1, -1}, {1, 0}, {1, 1}, // bottom row
+	}
+
+	for _, dir := range directions {
+		newRow := row + dir[0]
+		newCol := col + dir[1]
+
+		// Same bounds checking as Part1, but with [][]byte instead of []string
+		if newRow >= 0 && newRow < len(grid) &&
+			newCol >= 0 && newCol < len(grid[newRow]) &&
+			grid[newRow][newCol] == '@' {
+			adjacentCount++
+		}
+	}
+
+	return adjacentCount < 4
+}
@@ -0,0 +1,77 @@
+package day4
+
+// Semantics selects when a roll that becomes accessible is removed.
+//
+// Both semantics remove exactly the same rolls in the end: removing a roll
+// only ever lowers its neighbours' counts, so a roll that becomes accessible
+// stays accessible until it is removed. They differ in how the removals are
+// grouped into waves.
+type Semantics int
+
+const (
+	// WaveSynchronous removes every accessible roll at once, then looks again.
+	// This is the puzzle's rule: a wave only sees the grid as it was before it.
+	WaveSynchronous Semantics = iota
+
+	// ASAP sweeps the grid in reading order and removes a roll the moment it
+	// is accessible, so a removal can free rolls later in the same sweep.
+	// Each sweep is a wave; rolls freed behind the sweep wait for the next.
+	ASAP
+)
+
+// Removal is the result of removing accessible rolls until none are left.
+type Removal struct {
+	Total int      // rolls removed altogether
+	Waves []int    // rolls removed in each wave, in order
+	Grid  []string // the starting grid, every row padded to the same width
+
+	// Wave records, for every cell, the wave (starting at 1) that removed it,
+	// or 0 if it was never removed. Rows are as wide as the widest input row.
+	Wave [][]int
+}
+
+// removeAccessible is day 4's rule as an automaton: a roll with fewer than 4
+// rolls among its 8 neighbours is removed.
+func removeAccessible(update UpdatePolicy) Config {
+	return Config{
+		Neighbourhood: Moore(),
+		Rules:         []Rule{{From: '@', To: '.', Counts: '@', Compare: Less, Threshold: 4}},
+		Update:        update,
+	}
+}
+
+// RemoveAll removes accessible rolls until none remain.
+//
+// Why not rescan the grid every wave?
+// - Rescanning costs O(cells) per wave, O(waves × cells) overall
+// - A roll only becomes accessible when a neighbour is removed
+// - So after the first scan, only neighbours of removed rolls need rechecking
+// - The Automaton does exactly that for any rule, so this is just its config
+//
+// The wave semantics map onto the automaton's update policies: waves are
+// synchronous steps, ASAP sweeps are sequential ones.
+func RemoveAll(grid []string, semantics Semantics) Removal {
+	update := Synchronous
+	if semantics == ASAP {
+		update = Sequential
+	}
+
+	var removal Removal
+	cfg := removeAccessible(update)
+	cfg.OnChange = func(step, row, col int, _, _ byte) {
+		removal.Wave[row][col] = step
+	}
+
+	a := NewAutomaton(grid, cfg)
+	removal.Grid = a.Grid()
+	removal.Wave = make([][]int, a.height)
+	for row := range removal.Wave {
+		removal.Wave[row] = make([]int, a.width)
+	}
+
+	// Removal only ever lowers counts, so the run always reaches a fixed point
+	res := a.Run(0)
+	removal.Total = res.Total()
+	removal.Waves = res.Changed
+	return removal
+}
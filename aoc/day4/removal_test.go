@@ -0,0 +1,132 @@
+package day4
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const example = `..@@.@@@@.
+@@@.@.@.@@
+@@@@@.@.@@
+@.@@@@..@.
+@@.@@@@.@@
+.@@@@@@@.@
+.@.@.@.@@@
+@.@@@.@@@@
+.@@@@@@@@.
+@.@.@@@.@.`
+
+// rescanWaves is the original Part2 loop, recording the size of each wave
+func rescanWaves(lines []string) []int {
+	grid := make([][]byte, len(lines))
+	for i, line := range lines {
+		grid[i] = []byte(line)
+	}
+	var waves []int
+	for {
+		accessible := findAccessibleRolls(grid)
+		if len(accessible) == 0 {
+			return waves
+		}
+		for _, pos := range accessible {
+			grid[pos.row][pos.col] = '.'
+		}
+		waves = append(waves, len(accessible))
+	}
+}
+
+// sweepWaves removes rolls in reading order the moment they are accessible,
+// one full pass per wave: the ASAP reference
+func sweepWaves(lines []string) []int {
+	grid := make([][]byte, len(lines))
+	for i, line := range lines {
+		grid[i] = []byte(line)
+	}
+	var waves []int
+	for {
+		removed := 0
+		for row := range grid {
+			for col := range grid[row] {
+				if grid[row][col] == '@' && isAccessibleMutable(grid, row, col) {
+					grid[row][col] = '.'
+					removed++
+				}
+			}
+		}
+		if removed == 0 {
+			return waves
+		}
+		waves = append(waves, removed)
+	}
+}
+
+func randomGrid(r *rand.Rand) []string {
+	rows, cols := 1+r.Intn(12), 1+r.Intn(12)
+	density := 0.4 + 0.5*r.Float64()
+	lines := make([]string, rows)
+	for i := range lines {
+		var sb strings.Builder
+		for j := 0; j < cols; j++ {
+			if r.Float64() < density {
+				sb.WriteByte('@')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		lines[i] = sb.String()
+	}
+	return lines
+}
+
+func TestRemoveAllExample(t *testing.T) {
+	lines := strings.Split(example, "\n")
+
+	sync := RemoveAll(lines, WaveSynchronous)
+	if sync.Total != 43 {
+		t.Errorf("wave-synchronous total = %d, want 43", sync.Total)
+	}
+	if want := []int{13, 12, 7, 5, 2, 1, 1, 1, 1}; !reflect.DeepEqual(sync.Waves, want) {
+		t.Errorf("wave-synchronous waves = %v, want %v", sync.Waves, want)
+	}
+
+	asap := RemoveAll(lines, ASAP)
+	if asap.Total != 43 {
+		t.Errorf("ASAP total = %d, want 43", asap.Total)
+	}
+	if len(asap.Waves) >= len(sync.Waves) {
+		t.Errorf("ASAP took %d waves, expected fewer than %d", len(asap.Waves), len(sync.Waves))
+	}
+}
+
+func TestRemoveAllMatchesRescanning(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 500; trial++ {
+		lines := randomGrid(r)
+		if got, want := RemoveAll(lines, WaveSynchronous).Waves, rescanWaves(lines); !reflect.DeepEqual(got, want) {
+			t.Fatalf("wave-synchronous on\n%s\ngot %v, want %v", strings.Join(lines, "\n"), got, want)
+		}
+		if got, want := RemoveAll(lines, ASAP).Waves, sweepWaves(lines); !reflect.DeepEqual(got, want) {
+			t.Fatalf("ASAP on\n%s\ngot %v, want %v", strings.Join(lines, "\n"), got, want)
+		}
+	}
+}
+
+func TestRemoveAllRecordsWaves(t *testing.T) {
+	lines := strings.Split(example, "\n")
+	removal := RemoveAll(lines, WaveSynchronous)
+
+	perWave := make([]int, len(removal.Waves)+1)
+	for row, cells := range removal.Wave {
+		for col, wave := range cells {
+			if wave > 0 && lines[row][col] != '@' {
+				t.Fatalf("(%d,%d) removed in wave %d but was never a roll", row, col, wave)
+			}
+			perWave[wave]++
+		}
+	}
+	if !reflect.DeepEqual(perWave[1:], removal.Waves) {
+		t.Errorf("cells per wave %v, want %v", perWave[1:], removal.Waves)
+	}
+}
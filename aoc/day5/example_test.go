@@ -0,0 +1,24 @@
+package day5
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte(example+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []int{3, 14} {
+		got, err := Parts[i](path)
+		if err != nil {
+			t.Fatalf("part %d: %v", i+1, err)
+		}
+		if got != want {
+			t.Errorf("part %d = %d, want %d", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package day5
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const example = `3-5
+10-14
+16-20
+12-18
+
+1
+5
+8
+11
+17
+32`
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{example, "", "\n", "1-2", "\n7\n8", "1-2\n\n\n3"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		db, err := NewParser(strings.NewReader(input)).Parse()
+		if err != nil {
+			return
+		}
+
+		formatted := db.Format()
+		again, err := NewParser(strings.NewReader(formatted)).Parse()
+		if err != nil {
+			t.Fatalf("reparsing %q (from %q): %v", formatted, input, err)
+		}
+		if !reflect.DeepEqual(again, db) {
+			t.Fatalf("round trip of %q: got %+v, want %+v", input, again, db)
+		}
+	})
+}
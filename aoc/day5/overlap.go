@@ -0,0 +1,192 @@
+package day5
+
+import (
+	"math"
+	"sort"
+
+	"adv2025/aoc/interval"
+)
+
+// Span is a run of consecutive IDs all covered by the same number of ranges.
+type Span struct {
+	Range Range
+	Depth int // how many fresh ranges contain each ID in the span
+}
+
+// Coverage records how many fresh ranges cover every ID.
+//
+// Algorithm: sweep line
+// Each range [s, e] adds 1 to the depth at s and takes it away again at e+1.
+// Sorting those events and walking them in order gives the depth between each
+// pair of consecutive event positions, so the whole number line is described
+// by at most 2n spans. That takes O(n log n) once; afterwards depth lookups
+// are binary searches and the other queries are scans over the spans.
+//
+// Example: 3-5, 10-14, 16-20, 12-18
+// - Depth 1 on 3-5, 10-11, 19-20
+// - Depth 2 on 12-14 and 16-18 (15 is only in 12-18, so depth 1)
+type Coverage struct {
+	spans []Span // increasing, depth > 0, neighbours never touch with equal depth
+}
+
+// NewCoverage sweeps the ranges once. Empty ranges (End before Start) cover
+// nothing.
+func NewCoverage(ranges []Range) Coverage {
+	// Events: +1 where a range starts, -1 just after it ends. A range ending at
+	// math.MaxInt has nowhere to put its -1 and simply never ends.
+	delta := map[int]int{}
+	for _, r := range ranges {
+		if r.IsEmpty() {
+			continue
+		}
+		delta[r.Start]++
+		if r.End < math.MaxInt {
+			delta[r.End+1]--
+		}
+	}
+	positions := make([]int, 0, len(delta))
+	for p, d := range delta {
+		if d != 0 {
+			positions = append(positions, p)
+		}
+	}
+	sort.Ints(positions)
+
+	var c Coverage
+	depth := 0
+	for i, p := range positions {
+		depth += delta[p]
+		if depth == 0 {
+			continue
+		}
+		end := math.MaxInt
+		if i+1 < len(positions) {
+			end = positions[i+1] - 1
+		}
+		c.spans = append(c.spans, Span{Range: Range{Start: p, End: end}, Depth: depth})
+	}
+	return c
+}
+
+// Spans returns every covered span in increasing order.
+func (c Coverage) Spans() []Span {
+	return append([]Span(nil), c.spans...)
+}
+
+// Depth returns how many ranges contain id, in O(log n).
+func (c Coverage) Depth(id int) int {
+	i := sort.Search(len(c.spans), func(i int) bool { return c.spans[i].Range.End >= id })
+	if i < len(c.spans) && c.spans[i].Range.Start <= id {
+		return c.spans[i].Depth
+	}
+	return 0
+}
+
+// Deepest returns the highest depth and the spans that reach it, merged where
+// they touch. Depth 0 with no spans means there were no ranges at all.
+func (c Coverage) Deepest() (int, []Range) {
+	deepest := 0
+	for _, s := range c.spans {
+		deepest = max(deepest, s.Depth)
+	}
+	if deepest == 0 {
+		return 0, nil
+	}
+	return deepest, c.Exactly(deepest).Intervals()
+}
+
+// Exactly returns the IDs covered by exactly k ranges, for k >= 1. (Infinitely
+// many IDs are covered by none; use Complement on AtLeast(1) within a bound.)
+func (c Coverage) Exactly(k int) interval.Set {
+	return c.matching(func(depth int) bool { return depth == k })
+}
+
+// AtLeast returns the IDs covered by k or more ranges, for k >= 1.
+func (c Coverage) AtLeast(k int) interval.Set {
+	return c.matching(func(depth int) bool { return depth >= k })
+}
+
+func (c Coverage) matching(keep func(depth int) bool) interval.Set {
+	var ranges []Range
+	for _, s := range c.spans {
+		if keep(s.Depth) {
+			ranges = append(ranges, s.Range)
+		}
+	}
+	return interval.NewSet(ranges...)
+}
+
+// IntervalTree answers "which ranges contain X" without scanning them all.
+//
+// Data structure: augmented binary search tree, stored implicitly
+// The ranges are sorted by start and the tree is the binary search over that
+// array: the root is the middle element and each half is a subtree. Every node
+// also records the largest End in its subtree, which lets a query skip:
+// - any subtree whose largest End is before X (nothing there reaches X)
+// - everything right of a node starting after X (those start later still)
+//
+// Building is O(n log n). A query for k matching ranges is O((k+1) log n),
+// and never worse than O(n): every subtree it enters holds a match or lies on
+// the binary-search path for the ID, so it walks one root-to-leaf path per
+// match plus that one. (A centred interval tree would reach O(log n + k), at the cost of
+// sorting ranges into every node.) Queries can be answered one at a time as
+// IDs arrive.
+type IntervalTree struct {
+	ranges []Range // sorted by Start, ties in input order
+	maxEnd []int   // maxEnd[mid]: largest End in the subtree rooted at mid
+}
+
+// NewIntervalTree builds a tree over the ranges. Empty ranges are dropped, as
+// they contain nothing.
+func NewIntervalTree(ranges []Range) *IntervalTree {
+	t := &IntervalTree{}
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			t.ranges = append(t.ranges, r)
+		}
+	}
+	sort.SliceStable(t.ranges, func(i, j int) bool { return t.ranges[i].Start < t.ranges[j].Start })
+	t.maxEnd = make([]int, len(t.ranges))
+	t.build(0, len(t.ranges))
+	return t
+}
+
+// build fills maxEnd for the subtree over ranges[lo:hi] and returns its largest End.
+func (t *IntervalTree) build(lo, hi int) int {
+	if lo >= hi {
+		return math.MinInt
+	}
+	mid := (lo + hi) / 2
+	t.maxEnd[mid] = max(t.ranges[mid].End, t.build(lo, mid), t.build(mid+1, hi))
+	return t.maxEnd[mid]
+}
+
+// Containing returns the ranges that contain id, ordered by start.
+func (t *IntervalTree) Containing(id int) []Range {
+	var found []Range
+	t.query(0, len(t.ranges), id, &found)
+	return found
+}
+
+func (t *IntervalTree) query(lo, hi, id int, found *[]Range) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	if t.maxEnd[mid] < id {
+		return
+	}
+	t.query(lo, mid, id, found)
+	if t.ranges[mid].Start > id {
+		return
+	}
+	if t.ranges[mid].End >= id {
+		*found = append(*found, t.ranges[mid])
+	}
+	t.query(mid+1, hi, id, found)
+}
+
+// Len returns the number of ranges in the tree.
+func (t *IntervalTree) Len() int {
+	return len(t.ranges)
+}
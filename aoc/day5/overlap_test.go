@@ -0,0 +1,113 @@
+package day5
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func exampleRanges(t *testing.T) []Range {
+	t.Helper()
+	db, err := NewParser(strings.NewReader(example)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.FreshRanges
+}
+
+func TestCoverageExample(t *testing.T) {
+	c := NewCoverage(exampleRanges(t))
+
+	for id, want := range map[int]int{2: 0, 3: 1, 11: 1, 12: 2, 15: 1, 17: 2, 20: 1, 21: 0} {
+		if got := c.Depth(id); got != want {
+			t.Errorf("Depth(%d) = %d, want %d", id, got, want)
+		}
+	}
+
+	depth, spans := c.Deepest()
+	if want := []Range{{Start: 12, End: 14}, {Start: 16, End: 18}}; depth != 2 || !reflect.DeepEqual(spans, want) {
+		t.Errorf("Deepest = %d %v, want 2 %v", depth, spans, want)
+	}
+	if got := c.Exactly(1).String(); got != "3-5,10-11,15-15,19-20" {
+		t.Errorf("Exactly(1) = %s", got)
+	}
+	if got := c.AtLeast(1).Len(); got != 14 {
+		t.Errorf("AtLeast(1) covers %d IDs, want Part2's 14", got)
+	}
+}
+
+func randomRanges(r *rand.Rand) []Range {
+	ranges := make([]Range, r.Intn(10))
+	for i := range ranges {
+		start := r.Intn(40)
+		ranges[i] = Range{Start: start, End: start + r.Intn(12) - 1} // a few are empty
+	}
+	return ranges
+}
+
+func TestCoverageMatchesCounting(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 1000; trial++ {
+		ranges := randomRanges(r)
+		c := NewCoverage(ranges)
+		tree := NewIntervalTree(ranges)
+
+		deepest := 0
+		for id := -2; id < 55; id++ {
+			var want []Range
+			for _, rg := range ranges {
+				if rg.Contains(id) {
+					want = append(want, rg)
+				}
+			}
+			deepest = max(deepest, len(want))
+
+			if got := c.Depth(id); got != len(want) {
+				t.Fatalf("%v: Depth(%d) = %d, want %d", ranges, id, got, len(want))
+			}
+			for k := 1; k <= 3; k++ {
+				if got := c.Exactly(k).Contains(id); got != (len(want) == k) {
+					t.Fatalf("%v: Exactly(%d).Contains(%d) = %v", ranges, k, id, got)
+				}
+			}
+
+			got := tree.Containing(id)
+			if len(got) != len(want) {
+				t.Fatalf("%v: Containing(%d) = %v, want %v", ranges, id, got, want)
+			}
+			for _, w := range want {
+				found := false
+				for _, g := range got {
+					found = found || g == w
+				}
+				if !found {
+					t.Fatalf("%v: Containing(%d) = %v is missing %v", ranges, id, got, w)
+				}
+			}
+		}
+
+		if got, _ := c.Deepest(); got != deepest {
+			t.Fatalf("%v: Deepest depth %d, want %d", ranges, got, deepest)
+		}
+	}
+}
+
+func TestCoverageToMaxInt(t *testing.T) {
+	c := NewCoverage([]Range{{Start: math.MaxInt - 5, End: math.MaxInt}, {Start: math.MaxInt - 1, End: math.MaxInt}})
+	if c.Depth(math.MaxInt) != 2 || c.Depth(math.MaxInt-2) != 1 {
+		t.Errorf("spans %v", c.Spans())
+	}
+}
+
+func TestIntervalTreeOrder(t *testing.T) {
+	tree := NewIntervalTree(exampleRanges(t))
+	want := []Range{{Start: 10, End: 14}, {Start: 12, End: 18}}
+	if got := tree.Containing(13); !reflect.DeepEqual(got, want) {
+		t.Errorf("Containing(13) = %v, want %v", got, want)
+	}
+	if got := tree.Containing(7); got != nil {
+		t.Errorf("Containing(7) = %v, want none", got)
+	}
+}
@@ -7,18 +7,15 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"adv2025/aoc/interval"
 )
 
 // Range represents an inclusive range of ingredient IDs.
-type Range struct {
-	Start int
-	End   int
-}
-
-// Contains checks if a value falls within the range (inclusive).
-func (r Range) Contains(value int) bool {
-	return value >= r.Start && value <= r.End
-}
+//
+// It is an alias rather than a new type so that ranges go straight into an
+// interval.Set, and keep Contains and String ("3-5", as in the input).
+type Range = interval.Interval
 
 // Database represents the ingredient database with fresh ranges and available IDs.
 type Database struct {
@@ -26,6 +23,22 @@ type Database struct {
 	AvailableIDs []int
 }
 
+// Format writes the database back in the input format accepted by Parser.Parse:
+// one range per line, a blank line, then one ID per line.
+func (db *Database) Format() string {
+	var sb strings.Builder
+	for _, r := range db.FreshRanges {
+		sb.WriteString(r.String())
+		sb.WriteByte('\n')
+	}
+	sb.WriteByte('\n')
+	for _, id := range db.AvailableIDs {
+		sb.WriteString(strconv.Itoa(id))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
 // Parser reads and parses input for Day 5.
 //
 // Go Best Practice: Accept interfaces, return concrete types
@@ -61,26 +74,15 @@ func (p *Parser) Parse() (*Database, error) {
 		}
 
 		if parsingRanges {
-			parts := strings.Split(line, "-")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("invalid range format: %s", line)
-			}
-
-			start, err := strconv.Atoi(parts[0])
+			r, err := parseRange(line)
 			if err != nil {
-				return nil, fmt.Errorf("invalid start value in range %s: %w", line, err)
+				return nil, err
 			}
-
-			end, err := strconv.Atoi(parts[1])
-			if err != nil {
-				return nil, fmt.Errorf("invalid end value in range %s: %w", line, err)
-			}
-
-			db.FreshRanges = append(db.FreshRanges, Range{Start: start, End: end})
+			db.FreshRanges = append(db.FreshRanges, r)
 		} else {
-			id, err := strconv.Atoi(line)
+			id, err := parseID(line)
 			if err != nil {
-				return nil, fmt.Errorf("invalid ingredient ID %s: %w", line, err)
+				return nil, err
 			}
 			db.AvailableIDs = append(db.AvailableIDs, id)
 		}
@@ -93,6 +95,35 @@ func (p *Parser) Parse() (*Database, error) {
 	return db, nil
 }
 
+// parseRange parses a fresh range line such as "3-5".
+func parseRange(line string) (Range, error) {
+	parts := strings.Split(line, "-")
+	if len(parts) != 2 {
+		return Range{}, fmt.Errorf("invalid range format: %s", line)
+	}
+
+	start, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid start value in range %s: %w", line, err)
+	}
+
+	end, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid end value in range %s: %w", line, err)
+	}
+
+	return Range{Start: start, End: end}, nil
+}
+
+// parseID parses an available ingredient ID line.
+func parseID(line string) (int, error) {
+	id, err := strconv.Atoi(line)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ingredient ID %s: %w", line, err)
+	}
+	return id, nil
+}
+
 // FromFile creates a parser from a file path and parses the database immediately.
 func FromFile(path string) (*Database, error) {
 	file, err := os.Open(path)
@@ -4,28 +4,16 @@ import "fmt"
 
 // Part1 solves Day 5 Part 1: Count how many available ingredient IDs are fresh.
 // An ingredient ID is fresh if it falls within any of the fresh ranges (inclusive).
+//
+// Checking every range for every ID costs O(n·m). Merging the ranges into an
+// interval.Set first leaves sorted, disjoint intervals, so each ID is a binary
+// search: O(n log n) to build, O(log n) per ID. Streaming (see Parser.Stream)
+// means the IDs are counted as they are read and never held in memory.
 func Part1(inputPath string) (int, error) {
-	db, err := FromFile(inputPath)
+	totals, err := StreamFile(inputPath, nil)
 	if err != nil {
 		return 0, fmt.Errorf("loading input: %w", err)
 	}
 
-	freshCount := 0
-	for _, id := range db.AvailableIDs {
-		if isFresh(id, db.FreshRanges) {
-			freshCount++
-		}
-	}
-
-	return freshCount, nil
-}
-
-// isFresh checks if an ingredient ID is fresh (falls within any range).
-func isFresh(id int, ranges []Range) bool {
-	for _, r := range ranges {
-		if r.Contains(id) {
-			return true
-		}
-	}
-	return false
+	return totals.Fresh, nil
 }
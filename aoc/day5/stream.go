@@ -0,0 +1,92 @@
+package day5
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"adv2025/aoc/interval"
+)
+
+// Totals counts the IDs classified so far.
+type Totals struct {
+	Fresh, Spoiled int
+}
+
+// Seen returns how many IDs have been classified.
+func (t Totals) Seen() int {
+	return t.Fresh + t.Spoiled
+}
+
+// Stream reads the database like Parse, but never stores the available IDs.
+//
+// The ranges come first in the input, so they can all be read and merged into
+// an interval.Set before the first ID arrives. From then on every ID is
+// classified the moment it is read, by a binary search over the merged ranges,
+// and handed to visit (which may be nil). Memory is O(ranges) however many IDs
+// follow, so an ID section of hundreds of millions of lines costs no more than
+// the example; only the time grows, at O(log ranges) per ID.
+//
+// A non-nil error from visit stops the stream and is returned as is, together
+// with the totals up to and including that ID.
+func (p *Parser) Stream(visit func(id int, fresh bool, totals Totals) error) (Totals, error) {
+	var totals Totals
+	var ranges []Range
+	var fresh interval.Set
+	parsingRanges := true
+
+	for p.scanner.Scan() {
+		line := strings.TrimSpace(p.scanner.Text())
+
+		if line == "" {
+			if parsingRanges {
+				fresh = interval.NewSet(ranges...)
+				ranges = nil
+			}
+			parsingRanges = false
+			continue
+		}
+
+		if parsingRanges {
+			r, err := parseRange(line)
+			if err != nil {
+				return totals, err
+			}
+			ranges = append(ranges, r)
+			continue
+		}
+
+		id, err := parseID(line)
+		if err != nil {
+			return totals, err
+		}
+		isFresh := fresh.Contains(id)
+		if isFresh {
+			totals.Fresh++
+		} else {
+			totals.Spoiled++
+		}
+		if visit != nil {
+			if err := visit(id, isFresh, totals); err != nil {
+				return totals, err
+			}
+		}
+	}
+
+	if err := p.scanner.Err(); err != nil {
+		return totals, fmt.Errorf("reading input: %w", err)
+	}
+
+	return totals, nil
+}
+
+// StreamFile opens a file and streams it; see Parser.Stream.
+func StreamFile(path string, visit func(id int, fresh bool, totals Totals) error) (Totals, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Totals{}, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	return NewParser(file).Stream(visit)
+}
@@ -0,0 +1,123 @@
+package day5
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestStreamMatchesParse(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		db := &Database{}
+		for i := r.Intn(8); i > 0; i-- {
+			start := r.Intn(40)
+			db.FreshRanges = append(db.FreshRanges, Range{Start: start, End: start + r.Intn(10)})
+		}
+		for i := r.Intn(30); i > 0; i-- {
+			db.AvailableIDs = append(db.AvailableIDs, r.Intn(60)-5)
+		}
+		input := db.Format()
+
+		var got []bool
+		totals, err := NewParser(strings.NewReader(input)).Stream(func(id int, fresh bool, _ Totals) error {
+			got = append(got, fresh)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := Totals{}
+		for i, id := range db.AvailableIDs {
+			fresh := false
+			for _, rg := range db.FreshRanges {
+				fresh = fresh || rg.Contains(id)
+			}
+			if fresh != got[i] {
+				t.Fatalf("%q: ID %d classified fresh=%v", input, id, got[i])
+			}
+			if fresh {
+				want.Fresh++
+			} else {
+				want.Spoiled++
+			}
+		}
+		if totals != want {
+			t.Fatalf("%q: totals %+v, want %+v", input, totals, want)
+		}
+	}
+}
+
+func TestStreamReportsRunningTotals(t *testing.T) {
+	var lines []string
+	_, err := NewParser(strings.NewReader(example)).Stream(func(id int, fresh bool, totals Totals) error {
+		lines = append(lines, fmt.Sprintf("%d %v %d/%d", id, fresh, totals.Fresh, totals.Seen()))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1 false 0/1,5 true 1/2,8 false 1/3,11 true 2/4,17 true 3/5,32 false 3/6"
+	if got := strings.Join(lines, ","); got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestStreamStopsOnVisitError(t *testing.T) {
+	stop := errors.New("stop")
+	totals, err := NewParser(strings.NewReader(example)).Stream(func(id int, _ bool, _ Totals) error {
+		if id == 8 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || totals.Seen() != 3 {
+		t.Errorf("got %+v, %v; want 3 IDs seen and the visitor's error", totals, err)
+	}
+}
+
+func TestStreamBadID(t *testing.T) {
+	_, err := NewParser(strings.NewReader("1-2\n\n1\nx\n")).Stream(nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid ingredient ID x") {
+		t.Errorf("got %v", err)
+	}
+}
+
+// idSource generates an input with n IDs on the fly, never holding them
+type idSource struct {
+	header string
+	n, i   int
+	buf    []byte
+}
+
+func (s *idSource) Read(p []byte) (int, error) {
+	for len(s.buf) < len(p) && s.i < s.n {
+		if s.i == 0 {
+			s.buf = append(s.buf, s.header...)
+		}
+		s.buf = fmt.Appendf(s.buf, "%d\n", s.i%1000)
+		s.i++
+	}
+	if len(s.buf) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+func TestStreamManyIDs(t *testing.T) {
+	const n = 1_000_000
+	src := &idSource{header: "100-199\n500-549\n\n", n: n}
+	totals, err := NewParser(src).Stream(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Totals{Fresh: n / 1000 * 150, Spoiled: n / 1000 * 850}); totals != want {
+		t.Errorf("totals %+v, want %+v", totals, want)
+	}
+}
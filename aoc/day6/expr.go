@@ -0,0 +1,266 @@
+package day6
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Operators lists every operation a worksheet can use, one character each so
+// that it fits under a problem in the operator row:
+//
+//	'+' sum        '-' difference   '*' product
+//	'/' quotient   '%' remainder    '^' power
+//	'<' minimum    '>' maximum
+//
+// Every operator folds left to right over the problem's numbers, so the
+// column 2, 3, 2 under '^' is (2^3)^2 = 64 and under '-' is 2-3-2 = -3.
+// Division and remainder truncate toward zero, as in Go.
+const Operators = "+-*/%^<>"
+
+// IsOperator reports whether op is one of Operators.
+func IsOperator(op rune) bool {
+	return op != 0 && strings.ContainsRune(Operators, op)
+}
+
+// Errors wrapped by CellError.
+var (
+	ErrUnknownOperator  = errors.New("unknown operator")
+	ErrMissingOperator  = errors.New("problem has no operator")
+	ErrMalformedNumber  = errors.New("malformed number")
+	ErrDivisionByZero   = errors.New("division by zero")
+	ErrNegativeExponent = errors.New("negative exponent")
+	ErrTooLarge         = errors.New("result too large")
+)
+
+// maxBits bounds big results: a few chained powers would otherwise grow
+// without limit (9999^9999 alone has 40,000 digits).
+const maxBits = 1 << 20
+
+// CellError locates a problem in the worksheet. Line and Column are 1-based;
+// for evaluation errors they point at the problem's operator.
+type CellError struct {
+	Line, Column int
+	Text         string // the offending cell, or the operator
+	Err          error
+}
+
+func (e *CellError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %q: %v", e.Line, e.Column, e.Text, e.Err)
+}
+
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+// Value is a problem's result: an int while it fits, a big.Int once it doesn't.
+type Value struct {
+	small int
+	large *big.Int // nil unless the result overflows an int
+}
+
+// Int returns the value as an int; ok is false if it does not fit.
+func (v Value) Int() (n int, ok bool) {
+	return v.small, v.large == nil
+}
+
+// BigInt returns the value as an arbitrarily large number.
+func (v Value) BigInt() *big.Int {
+	if v.large != nil {
+		return new(big.Int).Set(v.large)
+	}
+	return big.NewInt(int64(v.small))
+}
+
+// IsBig reports whether the value needed a big.Int.
+func (v Value) IsBig() bool {
+	return v.large != nil
+}
+
+func (v Value) String() string {
+	return v.BigInt().String()
+}
+
+// Calculate computes the result of this problem as an int.
+//
+// It predates Evaluate and keeps its old contract: no error, and a result
+// too large for an int wraps around as plain int arithmetic would (it is
+// Evaluate's result modulo 2^64). Problems Evaluate rejects, such as division
+// by zero, give 0. Use Evaluate to tell these cases apart.
+func (p Problem) Calculate() int {
+	v, err := p.Evaluate()
+	if err != nil {
+		return 0
+	}
+	if n, ok := v.Int(); ok {
+		return n
+	}
+	low := new(big.Int).And(v.large, new(big.Int).SetUint64(math.MaxUint64))
+	return int(int64(low.Uint64()))
+}
+
+// Evaluate folds the problem's operator over its numbers.
+//
+// Overflow detection: checked int arithmetic, then big.Int
+// Almost every problem fits in an int, so each step is first tried as an int
+// operation that reports overflow instead of wrapping around. At the first
+// overflow the running result moves to a big.Int and the rest of the fold
+// continues there. The result goes back to an int if it fits again, so only
+// genuinely huge results are big.
+func (p Problem) Evaluate() (Value, error) {
+	if len(p.Numbers) == 0 {
+		return Value{}, nil
+	}
+	if !IsOperator(p.Operation) {
+		return Value{}, p.errorf(ErrUnknownOperator)
+	}
+
+	acc := p.Numbers[0]
+	i := 1
+	for ; i < len(p.Numbers); i++ {
+		next, ok, err := applyInt(p.Operation, acc, p.Numbers[i])
+		if err != nil {
+			return Value{}, p.errorf(err)
+		}
+		if !ok {
+			break
+		}
+		acc = next
+	}
+	if i == len(p.Numbers) {
+		return Value{small: acc}, nil
+	}
+
+	large := big.NewInt(int64(acc))
+	for ; i < len(p.Numbers); i++ {
+		if err := applyBig(p.Operation, large, p.Numbers[i]); err != nil {
+			return Value{}, p.errorf(err)
+		}
+	}
+	if large.IsInt64() {
+		return Value{small: int(large.Int64())}, nil
+	}
+	return Value{large: large}, nil
+}
+
+func (p Problem) errorf(err error) error {
+	return &CellError{Line: p.Line, Column: p.Column, Text: string(p.Operation), Err: err}
+}
+
+// applyInt computes a op b; ok is false if the result overflows an int.
+func applyInt(op rune, a, b int) (result int, ok bool, err error) {
+	switch op {
+	case '+':
+		if (b > 0 && a > math.MaxInt-b) || (b < 0 && a < math.MinInt-b) {
+			return 0, false, nil
+		}
+		return a + b, true, nil
+	case '-':
+		if (b < 0 && a > math.MaxInt+b) || (b > 0 && a < math.MinInt+b) {
+			return 0, false, nil
+		}
+		return a - b, true, nil
+	case '*':
+		result, ok = mulInt(a, b)
+		return result, ok, nil
+	case '/', '%':
+		if b == 0 {
+			return 0, false, ErrDivisionByZero
+		}
+		if a == math.MinInt && b == -1 {
+			if op == '%' {
+				return 0, true, nil
+			}
+			return 0, false, nil
+		}
+		if op == '/' {
+			return a / b, true, nil
+		}
+		return a % b, true, nil
+	case '^':
+		return powInt(a, b)
+	case '<':
+		return min(a, b), true, nil
+	default: // '>'
+		return max(a, b), true, nil
+	}
+}
+
+func mulInt(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, false
+	}
+	return c, true
+}
+
+// powInt computes base^exp by repeated squaring.
+func powInt(base, exp int) (int, bool, error) {
+	if exp < 0 {
+		return 0, false, ErrNegativeExponent
+	}
+	result := 1
+	for exp > 0 {
+		var ok bool
+		if exp&1 == 1 {
+			if result, ok = mulInt(result, base); !ok {
+				return 0, false, nil
+			}
+		}
+		exp >>= 1
+		if exp > 0 {
+			if base, ok = mulInt(base, base); !ok {
+				return 0, false, nil
+			}
+		}
+	}
+	return result, true, nil
+}
+
+// applyBig sets acc to acc op b.
+func applyBig(op rune, acc *big.Int, b int) error {
+	y := big.NewInt(int64(b))
+	switch op {
+	case '+':
+		acc.Add(acc, y)
+	case '-':
+		acc.Sub(acc, y)
+	case '*':
+		acc.Mul(acc, y)
+	case '/', '%':
+		if b == 0 {
+			return ErrDivisionByZero
+		}
+		if op == '/' {
+			acc.Quo(acc, y)
+		} else {
+			acc.Rem(acc, y)
+		}
+	case '^':
+		if b < 0 {
+			return ErrNegativeExponent
+		}
+		// |acc|^b has about b·bits(acc) bits; refuse before computing it
+		if acc.BitLen() > 1 && b > maxBits/acc.BitLen() {
+			return ErrTooLarge
+		}
+		acc.Exp(acc, y, nil)
+	case '<':
+		if acc.Cmp(y) > 0 {
+			acc.Set(y)
+		}
+	default: // '>'
+		if acc.Cmp(y) < 0 {
+			acc.Set(y)
+		}
+	}
+	if acc.BitLen() > maxBits {
+		return ErrTooLarge
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package day6
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrMissingNumbers is returned for a problem with an operator but no numbers
+var ErrMissingNumbers = errors.New("problem has no numbers")
+
+// String names the reading mode
+func (m ReadingMode) String() string {
+	if m == RightToLeft {
+		return "right to left"
+	}
+	return "left to right"
+}
+
+// Alignment describes where a problem's numbers sit within its columns, in
+// the problem's reading direction: rows against the left or right edge when
+// read left to right, columns against the top or bottom when read right to left
+type Alignment int
+
+const (
+	AlignFull   Alignment = iota // every number fills the problem's width (or height)
+	AlignLeft                    // rows start at the first column
+	AlignRight                   // rows end at the last column
+	AlignTop                     // columns start on the first row
+	AlignBottom                  // columns end on the last number row
+	AlignRagged                  // no common edge
+)
+
+func (a Alignment) String() string {
+	return [...]string{"full", "left-aligned", "right-aligned", "top-aligned", "bottom-aligned", "ragged"}[a]
+}
+
+// Layout is what AnalyzeLayout found out about one problem
+type Layout struct {
+	Start, End     int // 1-based columns spanned, inclusive
+	Operator       rune
+	OperatorColumn int
+	Mode           ReadingMode
+	Alignment      Alignment
+}
+
+func (l Layout) String() string {
+	return fmt.Sprintf("columns %d-%d, %c at column %d, read %v, %v",
+		l.Start, l.End, l.Operator, l.OperatorColumn, l.Mode, l.Alignment)
+}
+
+// AnalyzeLayout finds every problem in a worksheet and explains how it is laid out.
+//
+// Problems are separated by columns that are blank on every line, operator row
+// included, so both reading modes agree on where problems are; they differ
+// only in how the cells inside a problem form numbers. That cannot be told
+// from the text (the puzzle reads the same worksheet both ways), so modes says
+// it: modes[i] reads problem i, or a single mode reads every problem.
+func AnalyzeLayout(lines []string, modes []ReadingMode) ([]Layout, error) {
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("input too short: need at least 2 lines")
+	}
+	if len(modes) == 0 {
+		return nil, fmt.Errorf("no reading mode given")
+	}
+	numberLines, opText := lines[:len(lines)-1], lines[len(lines)-1]
+	opLine := len(lines)
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	blank := func(col int) bool {
+		for _, line := range lines {
+			if col < len(line) && line[col] != ' ' {
+				return false
+			}
+		}
+		return true
+	}
+
+	var layouts []Layout
+	for col := 0; col < width; col++ {
+		if blank(col) {
+			continue
+		}
+		start := col
+		for col+1 < width && !blank(col+1) {
+			col++
+		}
+		layouts = append(layouts, Layout{Start: start + 1, End: col + 1})
+	}
+
+	if len(modes) > 1 && len(modes) != len(layouts) {
+		return nil, fmt.Errorf("%d reading modes for %d problems", len(modes), len(layouts))
+	}
+
+	for i := range layouts {
+		l := &layouts[i]
+		l.Mode = modes[min(i, len(modes)-1)]
+
+		cell := strings.TrimSpace(columns(opText, l.Start, l.End))
+		if cell == "" {
+			return nil, &CellError{Line: opLine, Column: l.Start, Err: ErrMissingOperator}
+		}
+		l.OperatorColumn = l.Start + strings.IndexFunc(columns(opText, l.Start, l.End), func(r rune) bool { return r != ' ' })
+		op, err := parseOperator(cell, opLine, l.OperatorColumn)
+		if err != nil {
+			return nil, err
+		}
+		l.Operator = op
+
+		l.Alignment = alignment(numberLines, *l)
+	}
+	return layouts, nil
+}
+
+// columns returns the part of line in the 1-based columns start..end
+func columns(line string, start, end int) string {
+	if start > len(line) {
+		return ""
+	}
+	return line[start-1 : min(end, len(line))]
+}
+
+// alignment finds the edge every cell touches. Left to right, a cell is a
+// row's text within the problem; right to left, it is a column's text.
+func alignment(numberLines []string, l Layout) Alignment {
+	first, last := l.Start-1, l.End-1
+	if l.Mode == RightToLeft {
+		first, last = 0, len(numberLines)-1
+	}
+
+	touchesFirst, touchesLast := true, true
+	cell := func(lo, hi int) {
+		if lo < 0 {
+			return // empty cell
+		}
+		touchesFirst = touchesFirst && lo == first
+		touchesLast = touchesLast && hi == last
+	}
+
+	if l.Mode == RightToLeft {
+		for col := l.Start - 1; col < l.End; col++ {
+			lo, hi := -1, -1
+			for row, line := range numberLines {
+				if col < len(line) && line[col] != ' ' {
+					if lo < 0 {
+						lo = row
+					}
+					hi = row
+				}
+			}
+			cell(lo, hi)
+		}
+	} else {
+		for _, line := range numberLines {
+			text := columns(line, l.Start, l.End)
+			lo := strings.IndexFunc(text, func(r rune) bool { return r != ' ' })
+			hi := len(strings.TrimRight(text, " ")) - 1
+			if lo >= 0 {
+				cell(first+lo, first+hi)
+			}
+		}
+	}
+
+	switch {
+	case touchesFirst && touchesLast:
+		return AlignFull
+	case touchesFirst && l.Mode == RightToLeft:
+		return AlignTop
+	case touchesLast && l.Mode == RightToLeft:
+		return AlignBottom
+	case touchesFirst:
+		return AlignLeft
+	case touchesLast:
+		return AlignRight
+	}
+	return AlignRagged
+}
+
+// ParseMixed extracts the problems of a worksheet whose problems need not all
+// be read the same way; modes is as for AnalyzeLayout. Each Problem records
+// the mode it was read with.
+//
+// Unlike ParseProblems, which splits left-to-right rows into fields, this
+// finds problems by their columns, so the operator row must line up under
+// the numbers; in exchange a problem may have fewer numbers than another.
+func ParseMixed(lines []string, modes []ReadingMode) ([]Problem, error) {
+	layouts, err := AnalyzeLayout(lines, modes)
+	if err != nil {
+		return nil, err
+	}
+	numberLines := lines[:len(lines)-1]
+
+	problems := make([]Problem, 0, len(layouts))
+	for _, l := range layouts {
+		var numbers []int
+		if l.Mode == RightToLeft {
+			numbers, err = readColumns(numberLines, l)
+		} else {
+			numbers, err = readRows(numberLines, l)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(numbers) == 0 {
+			return nil, &CellError{Line: len(lines), Column: l.OperatorColumn, Text: string(l.Operator), Err: ErrMissingNumbers}
+		}
+
+		problems = append(problems, Problem{
+			Numbers: numbers, Operation: l.Operator, Mode: l.Mode,
+			Line: len(lines), Column: l.OperatorColumn,
+		})
+	}
+	return problems, nil
+}
+
+// readRows reads a problem left to right: each row's text is a whole number
+func readRows(numberLines []string, l Layout) ([]int, error) {
+	var numbers []int
+	for row, line := range numberLines {
+		text := columns(line, l.Start, l.End)
+		lo := strings.IndexFunc(text, func(r rune) bool { return r != ' ' })
+		if lo < 0 {
+			continue // this problem has fewer numbers than the worksheet has rows
+		}
+		num, err := parseNumber(strings.TrimSpace(text), row+1, l.Start+lo)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, num)
+	}
+	return numbers, nil
+}
+
+// readColumns reads a problem right to left: each column's digits, top to
+// bottom, are one number, and the rightmost column comes first
+func readColumns(numberLines []string, l Layout) ([]int, error) {
+	var numbers []int
+	for col := l.End - 1; col >= l.Start-1; col-- {
+		var digits strings.Builder
+		top := 0
+		for row, line := range numberLines {
+			if col >= len(line) || line[col] == ' ' {
+				continue
+			}
+			if line[col] < '0' || line[col] > '9' {
+				return nil, &CellError{Line: row + 1, Column: col + 1, Text: line[col : col+1], Err: ErrMalformedNumber}
+			}
+			if digits.Len() == 0 {
+				top = row
+			}
+			digits.WriteByte(line[col])
+		}
+		if digits.Len() == 0 {
+			continue // only the operator row reaches this column
+		}
+
+		num, err := parseNumber(digits.String(), top+1, col+1)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, num)
+	}
+	return numbers, nil
+}
+
+// Render writes problems back out as a worksheet that ParseMixed reads, in the
+// given mode, into the same numbers and operators.
+//
+// Left to right, each number is a row, right-aligned within the problem's
+// width. Right to left, each number is a column written top to bottom, the
+// first number rightmost. The operator sits under the first column, as in the
+// puzzle, and problems are separated by one blank column.
+func Render(problems []Problem, mode ReadingMode) ([]string, error) {
+	return render(problems, func(Problem) ReadingMode { return mode })
+}
+
+// RenderMixed renders each problem in its own Mode, for ParseMixed to read back
+// with the same modes.
+func RenderMixed(problems []Problem) ([]string, error) {
+	return render(problems, func(p Problem) ReadingMode { return p.Mode })
+}
+
+func render(problems []Problem, modeOf func(Problem) ReadingMode) ([]string, error) {
+	type block struct {
+		width int
+		rows  []string
+		op    rune
+	}
+
+	blocks := make([]block, len(problems))
+	height := 1 // a worksheet has at least one number row
+	for i, p := range problems {
+		if len(p.Numbers) == 0 {
+			return nil, fmt.Errorf("problem %d: %w", i+1, ErrMissingNumbers)
+		}
+		if !IsOperator(p.Operation) {
+			return nil, fmt.Errorf("problem %d: %w %q", i+1, ErrUnknownOperator, p.Operation)
+		}
+
+		b := block{op: p.Operation}
+		texts := make([]string, len(p.Numbers))
+		for j, n := range p.Numbers {
+			texts[j] = strconv.Itoa(n)
+			b.width = max(b.width, len(texts[j]))
+		}
+
+		if modeOf(p) == RightToLeft {
+			// Column c holds number k-1-c; row r holds each number's r-th digit
+			k := len(texts)
+			b.width = k
+			for _, text := range texts {
+				if text[0] == '-' {
+					return nil, fmt.Errorf("problem %d: %w: %s cannot be written as a column of digits", i+1, ErrMalformedNumber, text)
+				}
+				for len(b.rows) < len(text) {
+					b.rows = append(b.rows, strings.Repeat(" ", k))
+				}
+			}
+			for c := 0; c < k; c++ {
+				text := texts[k-1-c]
+				for r := range text {
+					row := []byte(b.rows[r])
+					row[c] = text[r]
+					b.rows[r] = string(row)
+				}
+			}
+		} else {
+			for _, text := range texts {
+				b.rows = append(b.rows, fmt.Sprintf("%*s", b.width, text))
+			}
+		}
+
+		blocks[i] = b
+		height = max(height, len(b.rows))
+	}
+
+	lines := make([]string, height+1)
+	for r := range lines {
+		var sb strings.Builder
+		for i, b := range blocks {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+			cell := strings.Repeat(" ", b.width)
+			switch {
+			case r == height:
+				cell = string(b.op) + cell[1:]
+			case r < len(b.rows):
+				cell = b.rows[r]
+			}
+			sb.WriteString(cell)
+		}
+		lines[r] = strings.TrimRight(sb.String(), " ")
+	}
+	return lines, nil
+}
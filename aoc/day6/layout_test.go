@@ -0,0 +1,171 @@
+package day6
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeLayoutExample(t *testing.T) {
+	lines := strings.Split(example, "\n")
+
+	layouts, err := AnalyzeLayout(lines, []ReadingMode{LeftToRight})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"columns 1-3, * at column 1, read left to right, right-aligned",
+		"columns 5-7, + at column 5, read left to right, left-aligned",
+		"columns 9-11, * at column 9, read left to right, right-aligned",
+		"columns 13-15, + at column 13, read left to right, left-aligned",
+	}
+	for i, l := range layouts {
+		if l.String() != want[i] {
+			t.Errorf("problem %d: %v\nwant %s", i+1, l, want[i])
+		}
+	}
+
+	layouts, err = AnalyzeLayout(lines, []ReadingMode{RightToLeft})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, a := range []Alignment{AlignTop, AlignTop, AlignBottom, AlignBottom} {
+		if layouts[i].Alignment != a || layouts[i].Mode != RightToLeft {
+			t.Errorf("problem %d right to left: %v, want %v", i+1, layouts[i], a)
+		}
+	}
+}
+
+func TestAnalyzeLayoutModes(t *testing.T) {
+	lines := strings.Split(example, "\n")
+	if _, err := AnalyzeLayout(lines, []ReadingMode{LeftToRight, RightToLeft}); err == nil {
+		t.Error("two modes for four problems should be rejected")
+	}
+	if _, err := AnalyzeLayout(lines, nil); err == nil {
+		t.Error("no modes should be rejected")
+	}
+
+	modes := []ReadingMode{LeftToRight, RightToLeft, RightToLeft, LeftToRight}
+	problems, err := ParseMixed(lines, modes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Problems 1 and 4 as in Part 1, 2 and 3 as in Part 2
+	want := [][]int{{123, 45, 6}, {8, 248, 369}, {175, 581, 32}, {64, 23, 314}}
+	for i, p := range problems {
+		if !reflect.DeepEqual(p.Numbers, want[i]) || p.Mode != modes[i] {
+			t.Errorf("problem %d = %v read %v, want %v read %v", i+1, p.Numbers, p.Mode, want[i], modes[i])
+		}
+	}
+}
+
+func randomProblems(r *rand.Rand, modes []ReadingMode) []Problem {
+	problems := make([]Problem, len(modes))
+	for i := range problems {
+		p := Problem{Operation: rune(Operators[r.Intn(len(Operators))]), Mode: modes[i]}
+		for j := 1 + r.Intn(4); j > 0; j-- {
+			n := r.Intn(100000)
+			if r.Intn(3) == 0 {
+				n = r.Intn(10)
+			}
+			if modes[i] == LeftToRight && r.Intn(5) == 0 {
+				n = -n
+			}
+			p.Numbers = append(p.Numbers, n)
+		}
+		problems[i] = p
+	}
+	return problems
+}
+
+// sameProblems compares what was parsed, ignoring where it was found
+func sameProblems(a, b []Problem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(a[i].Numbers, b[i].Numbers) || a[i].Operation != b[i].Operation || a[i].Mode != b[i].Mode {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 500; trial++ {
+		mode := ReadingMode(r.Intn(2))
+		modes := make([]ReadingMode, 1+r.Intn(6))
+		for i := range modes {
+			modes[i] = mode
+		}
+		problems := randomProblems(r, modes)
+
+		lines, err := Render(problems, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseMixed(lines, []ReadingMode{mode})
+		if err != nil || !sameProblems(got, problems) {
+			t.Fatalf("%v rendered %v as\n%s\nread back %v (%v)", mode, problems, strings.Join(lines, "\n"), got, err)
+		}
+	}
+}
+
+func TestRenderMixedRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 500; trial++ {
+		modes := make([]ReadingMode, 1+r.Intn(6))
+		for i := range modes {
+			modes[i] = ReadingMode(r.Intn(2))
+		}
+		problems := randomProblems(r, modes)
+
+		lines, err := RenderMixed(problems)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseMixed(lines, modes)
+		if err != nil || !sameProblems(got, problems) {
+			t.Fatalf("rendered %v as\n%s\nread back %v (%v)", problems, strings.Join(lines, "\n"), got, err)
+		}
+	}
+}
+
+func TestExampleReRenders(t *testing.T) {
+	for _, mode := range []ReadingMode{LeftToRight, RightToLeft} {
+		problems, err := ParseProblems(strings.Split(example, "\n"), mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines, err := Render(problems, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := ParseMixed(lines, []ReadingMode{mode})
+		if err != nil || !sameProblems(again, problems) {
+			t.Errorf("%v: re-rendered example\n%s\nreads as %v (%v)", mode, strings.Join(lines, "\n"), again, err)
+		}
+	}
+
+	// Left to right the renderer right-aligns, which problem 1 already is
+	problems, _ := ParseProblems(strings.Split(example, "\n"), LeftToRight)
+	lines, _ := Render(problems[:1], LeftToRight)
+	if got := strings.Join(lines, "\n"); got != "123\n 45\n  6\n*" {
+		t.Errorf("problem 1 rendered as\n%s", got)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := Render([]Problem{{Numbers: []int{-5}, Operation: '+'}}, RightToLeft); !errors.Is(err, ErrMalformedNumber) {
+		t.Errorf("negative number right to left: %v", err)
+	}
+	if _, err := Render([]Problem{{Operation: '+'}}, LeftToRight); !errors.Is(err, ErrMissingNumbers) {
+		t.Errorf("no numbers: %v", err)
+	}
+	if _, err := Render([]Problem{{Numbers: []int{1}, Operation: '?'}}, LeftToRight); !errors.Is(err, ErrUnknownOperator) {
+		t.Errorf("unknown operator: %v", err)
+	}
+}
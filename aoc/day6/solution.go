@@ -2,6 +2,7 @@ package day6
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 )
@@ -9,25 +10,12 @@ import (
 // Problem represents a single vertical math problem
 type Problem struct {
 	Numbers   []int
-	Operation rune // '+' or '*'
-}
-
-// Calculate computes the result of this problem
-func (p Problem) Calculate() int {
-	if len(p.Numbers) == 0 {
-		return 0
-	}
+	Operation rune        // one of Operators
+	Mode      ReadingMode // how the problem's cells were read (see ParseMixed)
 
-	result := p.Numbers[0]
-	for i := 1; i < len(p.Numbers); i++ {
-		switch p.Operation {
-		case '+':
-			result += p.Numbers[i]
-		case '*':
-			result *= p.Numbers[i]
-		}
-	}
-	return result
+	// Line and Column (1-based) locate the operator in the worksheet, so that
+	// evaluation errors can point at the problem; both are 0 if unknown
+	Line, Column int
 }
 
 // ReadingMode determines how to interpret the worksheet columns
@@ -53,39 +41,78 @@ func ParseProblems(lines []string, mode ReadingMode) ([]Problem, error) {
 	return parseRightToLeft(numberLines, operationLine)
 }
 
+// field is a run of non-space characters and the 1-based column it starts at
+type field struct {
+	text string
+	col  int
+}
+
+// fields splits a line like strings.Fields, remembering where each field starts
+func fields(line string) []field {
+	var out []field
+	start := -1
+	for i := 0; i <= len(line); i++ {
+		if i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			out = append(out, field{text: line[start:i], col: start + 1})
+			start = -1
+		}
+	}
+	return out
+}
+
+// parseNumber converts a cell to an int, reporting where it was if it can't
+func parseNumber(text string, line, col int) (int, error) {
+	num, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, &CellError{Line: line, Column: col, Text: text, Err: fmt.Errorf("%w: %w", ErrMalformedNumber, err)}
+	}
+	return num, nil
+}
+
+// parseOperator checks an operator cell, which must be a single known operator
+func parseOperator(text string, line, col int) (rune, error) {
+	if len(text) != 1 || !IsOperator(rune(text[0])) {
+		return 0, &CellError{Line: line, Column: col, Text: text, Err: ErrUnknownOperator}
+	}
+	return rune(text[0]), nil
+}
+
 // parseLeftToRight interprets each space-separated field as a complete number (Part 1)
 func parseLeftToRight(numberLines []string, operationLine string) ([]Problem, error) {
 	// Parse each number line into fields
-	var allFields [][]string
+	var allFields [][]field
 	for _, line := range numberLines {
-		allFields = append(allFields, strings.Fields(line))
+		allFields = append(allFields, fields(line))
 	}
 
-	opFields := strings.Fields(operationLine)
-
+	opLine := len(numberLines) + 1
 	var problems []Problem
-	for i := range len(opFields) {
-		if len(opFields[i]) != 1 {
-			continue
-		}
-
-		op := rune(opFields[i][0])
-		if op != '+' && op != '*' {
-			continue
+	for i, opField := range fields(operationLine) {
+		op, err := parseOperator(opField.text, opLine, opField.col)
+		if err != nil {
+			return nil, err
 		}
 
 		// Extract the i-th field from each row
 		var numbers []int
-		for _, fields := range allFields {
-			if i < len(fields) {
-				if num, err := strconv.Atoi(fields[i]); err == nil {
-					numbers = append(numbers, num)
+		for row, fs := range allFields {
+			if i < len(fs) {
+				num, err := parseNumber(fs[i].text, row+1, fs[i].col)
+				if err != nil {
+					return nil, err
 				}
+				numbers = append(numbers, num)
 			}
 		}
 
 		if len(numbers) > 0 {
-			problems = append(problems, Problem{Numbers: numbers, Operation: op})
+			problems = append(problems, Problem{Numbers: numbers, Operation: op, Mode: LeftToRight, Line: opLine, Column: opField.col})
 		}
 	}
 
@@ -103,90 +130,124 @@ func parseRightToLeft(numberLines []string, operationLine string) ([]Problem, er
 
 	// Find problem column groups (separated by all-space columns)
 	type group struct {
-		cols []int
-		op   rune
+		cols  []int
+		op    rune
+		opCol int
 	}
 
 	var groups []group
-	var currentCols []int
-	var currentOp rune
+	var current group
+	opLine := len(numberLines) + 1
 
-	for col := maxLen - 1; col >= 0; col-- {
-		// Check if column is all spaces
+	for col := maxLen - 1; col >= -1; col-- {
+		// Check if column is all spaces (the column before the first ends the last group)
 		allSpaces := true
 		for _, line := range numberLines {
-			if col < len(line) && line[col] != ' ' {
+			if col >= 0 && col < len(line) && line[col] != ' ' {
 				allSpaces = false
 				break
 			}
 		}
 
 		if allSpaces {
-			if len(currentCols) > 0 {
-				groups = append(groups, group{cols: currentCols, op: currentOp})
-				currentCols = nil
-				currentOp = 0
-			}
-		} else {
-			currentCols = append(currentCols, col)
-			if col < len(operationLine) {
-				ch := rune(operationLine[col])
-				if ch == '+' || ch == '*' {
-					currentOp = ch
+			if len(current.cols) > 0 {
+				if current.op == 0 {
+					first := current.cols[len(current.cols)-1]
+					return nil, &CellError{Line: opLine, Column: first + 1, Text: "", Err: ErrMissingOperator}
 				}
+				groups = append(groups, current)
+				current = group{}
+			}
+			if col >= 0 && col < len(operationLine) && operationLine[col] != ' ' {
+				// An operator under a blank column belongs to no problem
+				return nil, &CellError{Line: opLine, Column: col + 1, Text: operationLine[col : col+1], Err: ErrMissingOperator}
 			}
+			continue
 		}
-	}
 
-	if len(currentCols) > 0 {
-		groups = append(groups, group{cols: currentCols, op: currentOp})
+		current.cols = append(current.cols, col)
+		if col < len(operationLine) && operationLine[col] != ' ' {
+			op, err := parseOperator(operationLine[col:col+1], opLine, col+1)
+			if err != nil {
+				return nil, err
+			}
+			if current.op != 0 {
+				return nil, &CellError{Line: opLine, Column: col + 1, Text: string(op),
+					Err: fmt.Errorf("%w: problem already has %c", ErrUnknownOperator, current.op)}
+			}
+			current.op, current.opCol = op, col+1
+		}
 	}
 
 	// Extract numbers from each group
 	var problems []Problem
 	for _, g := range groups {
-		if g.op == 0 {
-			continue
-		}
-
 		var numbers []int
 		for _, col := range g.cols {
 			// Read digits top-to-bottom in this column
 			var digits strings.Builder
-			for _, line := range numberLines {
-				if col < len(line) && line[col] >= '0' && line[col] <= '9' {
-					digits.WriteByte(line[col])
+			top := 0 // the row of the column's first digit
+			for row, line := range numberLines {
+				if col >= len(line) || line[col] == ' ' {
+					continue
+				}
+				if line[col] < '0' || line[col] > '9' {
+					return nil, &CellError{Line: row + 1, Column: col + 1, Text: line[col : col+1], Err: ErrMalformedNumber}
 				}
+				if digits.Len() == 0 {
+					top = row
+				}
+				digits.WriteByte(line[col])
 			}
 
-			if digits.Len() > 0 {
-				if num, err := strconv.Atoi(digits.String()); err == nil {
-					numbers = append(numbers, num)
-				}
+			num, err := parseNumber(digits.String(), top+1, col+1)
+			if err != nil {
+				return nil, err
 			}
+			numbers = append(numbers, num)
 		}
 
-		if len(numbers) > 0 {
-			problems = append(problems, Problem{Numbers: numbers, Operation: g.op})
-		}
+		problems = append(problems, Problem{Numbers: numbers, Operation: g.op, Mode: RightToLeft, Line: opLine, Column: g.opCol})
 	}
 
 	return problems, nil
 }
 
-// SolveWorksheet calculates the grand total using the specified reading mode
+// GrandTotal adds up every problem's answer, however large
+func GrandTotal(problems []Problem) (*big.Int, error) {
+	total := new(big.Int)
+	for _, problem := range problems {
+		v, err := problem.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+		if n, ok := v.Int(); ok {
+			total.Add(total, big.NewInt(int64(n)))
+		} else {
+			total.Add(total, v.BigInt())
+		}
+	}
+	return total, nil
+}
+
+// SolveWorksheet calculates the grand total using the specified reading mode.
+// Individual problems may overflow an int (see Problem.Evaluate); only a grand
+// total that does not fit is an error.
 func SolveWorksheet(lines []string, mode ReadingMode) (int, error) {
 	problems, err := ParseProblems(lines, mode)
 	if err != nil {
 		return 0, fmt.Errorf("parsing problems: %w", err)
 	}
 
-	grandTotal := 0
-	for _, problem := range problems {
-		grandTotal += problem.Calculate()
+	total, err := GrandTotal(problems)
+	if err != nil {
+		return 0, fmt.Errorf("evaluating problems: %w", err)
+	}
+	if !total.IsInt64() {
+		return 0, fmt.Errorf("grand total %s does not fit in an int", total)
 	}
 
-	return grandTotal, nil
+	return int(total.Int64()), nil
 }
 
 // For debugging: format a problem as a string
@@ -195,5 +256,20 @@ func (p Problem) String() string {
 	for i, n := range p.Numbers {
 		nums[i] = strconv.Itoa(n)
 	}
-	return fmt.Sprintf("%s %c %d", strings.Join(nums, fmt.Sprintf(" %c ", p.Operation)), '=', p.Calculate())
+
+	var expr string
+	switch p.Operation {
+	case '<':
+		expr = "min(" + strings.Join(nums, ", ") + ")"
+	case '>':
+		expr = "max(" + strings.Join(nums, ", ") + ")"
+	default:
+		expr = strings.Join(nums, fmt.Sprintf(" %c ", p.Operation))
+	}
+
+	v, err := p.Evaluate()
+	if err != nil {
+		return fmt.Sprintf("%s = error (%v)", expr, err)
+	}
+	return fmt.Sprintf("%s = %v", expr, v)
 }
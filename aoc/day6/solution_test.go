@@ -0,0 +1,209 @@
+package day6
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const example = "123 328  51 64 \n" +
+	" 45 64  387 23 \n" +
+	"  6 98  215 314\n" +
+	"*   +   *   +  "
+
+func TestExample(t *testing.T) {
+	lines := strings.Split(example, "\n")
+	for mode, want := range map[ReadingMode]int{LeftToRight: 4277556, RightToLeft: 3263827} {
+		got, err := SolveWorksheet(lines, mode)
+		if err != nil {
+			t.Fatalf("mode %d: %v", mode, err)
+		}
+		if got != want {
+			t.Errorf("mode %d = %d, want %d", mode, got, want)
+		}
+	}
+}
+
+func TestOperators(t *testing.T) {
+	for _, tc := range []struct {
+		op      rune
+		numbers []int
+		want    string
+	}{
+		{'+', []int{1, 2, 3}, "6"},
+		{'-', []int{2, 3, 2}, "-3"},
+		{'*', []int{2, 3, 4}, "24"},
+		{'/', []int{100, 3, 2}, "16"},
+		{'/', []int{-7, 2}, "-3"},
+		{'%', []int{100, 7, 4}, "2"},
+		{'%', []int{-7, 3}, "-1"},
+		{'^', []int{2, 3, 2}, "64"},
+		{'^', []int{7, 0}, "1"},
+		{'<', []int{5, 3, 9}, "3"},
+		{'>', []int{5, 3, 9}, "9"},
+		{'+', []int{42}, "42"},
+	} {
+		v, err := Problem{Numbers: tc.numbers, Operation: tc.op}.Evaluate()
+		if err != nil {
+			t.Errorf("%c %v: %v", tc.op, tc.numbers, err)
+			continue
+		}
+		if v.String() != tc.want || v.IsBig() {
+			t.Errorf("%c %v = %v (big %v), want %s", tc.op, tc.numbers, v, v.IsBig(), tc.want)
+		}
+	}
+}
+
+func TestOverflowSwitchesToBig(t *testing.T) {
+	for _, tc := range []struct {
+		op      rune
+		numbers []int
+		want    string
+	}{
+		{'+', []int{math.MaxInt, 1}, "9223372036854775808"},
+		{'-', []int{math.MinInt, 1}, "-9223372036854775809"},
+		{'*', []int{math.MaxInt, 2, 3}, "55340232221128654842"},
+		{'^', []int{10, 20}, "100000000000000000000"},
+		{'^', []int{2, 10, 7}, "1180591620717411303424"},
+		{'/', []int{math.MinInt, -1}, "9223372036854775808"},
+	} {
+		v, err := Problem{Numbers: tc.numbers, Operation: tc.op}.Evaluate()
+		if err != nil {
+			t.Fatalf("%c %v: %v", tc.op, tc.numbers, err)
+		}
+		if !v.IsBig() || v.String() != tc.want {
+			t.Errorf("%c %v = %v (big %v), want %s", tc.op, tc.numbers, v, v.IsBig(), tc.want)
+		}
+		if _, ok := v.Int(); ok {
+			t.Errorf("%c %v: Int should report that the result does not fit", tc.op, tc.numbers)
+		}
+	}
+
+	// Once big, the fold continues in big.Int
+	v, err := Problem{Numbers: []int{math.MaxInt, 2, 4}, Operation: '*'}.Evaluate()
+	want := new(big.Int).Mul(big.NewInt(math.MaxInt), big.NewInt(8))
+	if err != nil || v.BigInt().Cmp(want) != 0 {
+		t.Errorf("got %v, %v; want %v", v, err, want)
+	}
+
+	// and the result is an int again if it comes back within range
+	v, err = Problem{Numbers: []int{math.MaxInt, 10, -20}, Operation: '+'}.Evaluate()
+	if n, ok := v.Int(); err != nil || !ok || n != math.MaxInt-10 {
+		t.Errorf("overflow then back: got %v, %v", v, err)
+	}
+	v, err = Problem{Numbers: []int{math.MaxInt, 2, 1000}, Operation: '>'}.Evaluate()
+	if n, ok := v.Int(); err != nil || !ok || n != math.MaxInt {
+		t.Errorf("max never overflows: got %v, %v", v, err)
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	for _, tc := range []struct {
+		op      rune
+		numbers []int
+		want    int
+	}{
+		{'+', []int{328, 64, 98}, 490},
+		{'*', []int{123, 45, 6}, 33210},
+		{'+', nil, 0},
+		{'/', []int{1, 0}, 0},
+	} {
+		if got := (Problem{Numbers: tc.numbers, Operation: tc.op}).Calculate(); got != tc.want {
+			t.Errorf("%c %v = %d, want %d", tc.op, tc.numbers, got, tc.want)
+		}
+	}
+
+	// Overflow wraps around like int arithmetic, as it always did
+	maxInt, minInt := math.MaxInt, math.MinInt
+	for _, tc := range []struct {
+		op      rune
+		numbers []int
+		want    int
+	}{
+		{'+', []int{math.MaxInt, 1}, maxInt + 1},
+		{'*', []int{math.MaxInt, 2, 3}, maxInt * 2 * 3},
+		{'-', []int{math.MinInt, 1}, minInt - 1},
+	} {
+		if got := (Problem{Numbers: tc.numbers, Operation: tc.op}).Calculate(); got != tc.want {
+			t.Errorf("%c %v = %d, want %d", tc.op, tc.numbers, got, tc.want)
+		}
+	}
+}
+
+func TestEvaluationErrors(t *testing.T) {
+	for _, tc := range []struct {
+		op      rune
+		numbers []int
+		want    error
+	}{
+		{'/', []int{1, 0}, ErrDivisionByZero},
+		{'%', []int{math.MaxInt, 2, 0}, ErrDivisionByZero},
+		{'^', []int{2, -1}, ErrNegativeExponent},
+		{'^', []int{9999, 9999, 9999}, ErrTooLarge},
+		{'?', []int{1, 2}, ErrUnknownOperator},
+	} {
+		p := Problem{Numbers: tc.numbers, Operation: tc.op, Line: 4, Column: 9}
+		_, err := p.Evaluate()
+		var cell *CellError
+		if !errors.Is(err, tc.want) || !errors.As(err, &cell) || cell.Line != 4 || cell.Column != 9 {
+			t.Errorf("%c %v: got %v, want %v at line 4, column 9", tc.op, tc.numbers, err, tc.want)
+		}
+	}
+}
+
+func TestParseErrorsHavePositions(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		worksheet string
+		mode      ReadingMode
+		want      error
+		line, col int
+	}{
+		{"unknown operator", "1 2\n3 4\n+ ?", LeftToRight, ErrUnknownOperator, 3, 3},
+		{"long operator", "1 2\n3 4\n+ **", LeftToRight, ErrUnknownOperator, 3, 3},
+		{"malformed number", "1 2\n3 4x\n+ *", LeftToRight, ErrMalformedNumber, 2, 3},
+		{"number too large", "1 99999999999999999999\n+ +", LeftToRight, ErrMalformedNumber, 1, 3},
+		{"unknown operator", "12 34\n56 78\n+  &", RightToLeft, ErrUnknownOperator, 3, 4},
+		{"malformed digit", "12 34\n5x 78\n+  *", RightToLeft, ErrMalformedNumber, 2, 2},
+		{"missing operator", "12 34\n56 78\n+   ", RightToLeft, ErrMissingOperator, 3, 4},
+		{"column too large", "1 \n" + strings.Repeat(" 9\n", 20) + "+ ", RightToLeft, ErrMalformedNumber, 2, 2},
+		{"two operators", "12 34\n56 78\n+  **", RightToLeft, ErrUnknownOperator, 3, 4},
+	} {
+		_, err := ParseProblems(strings.Split(tc.worksheet, "\n"), tc.mode)
+		var cell *CellError
+		if !errors.Is(err, tc.want) || !errors.As(err, &cell) || cell.Line != tc.line || cell.Column != tc.col {
+			t.Errorf("%s (mode %d): got %v, want %v at line %d, column %d", tc.name, tc.mode, err, tc.want, tc.line, tc.col)
+		}
+	}
+}
+
+func TestNewOperatorsInWorksheet(t *testing.T) {
+	lines := []string{
+		"100 2  9 7",
+		"  7 10 4 3",
+		"%   ^  < -",
+	}
+	problems, err := ParseProblems(lines, LeftToRight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, err := GrandTotal(problems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(2 + 1024 + 4 + 4); total.Int64() != want {
+		t.Errorf("total = %v, want %d (problems %v)", total, want, problems)
+	}
+	if got := problems[2].String(); got != "min(9, 4) = 4" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestGrandTotalTooLargeForInt(t *testing.T) {
+	lines := []string{"9223372036854775807 1", "9223372036854775807 1", "+ +"}
+	if _, err := SolveWorksheet(lines, LeftToRight); err == nil || !strings.Contains(err.Error(), "does not fit") {
+		t.Errorf("got %v, want a grand total overflow error", err)
+	}
+}
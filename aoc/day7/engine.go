@@ -0,0 +1,424 @@
+package day7
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+)
+
+// Manifold is a parsed tachyon manifold: the grid and where beams enter.
+type Manifold struct {
+	// Sides says what happens to beams leaving a row through its ends;
+	// the zero value lets them exit, as in the puzzle.
+	Sides Side
+
+	grid    []string
+	width   int    // length of every row
+	sources []Beam // every 'S', in reading order
+	turns   bool   // some tile ('/', '\' or 'v') can change a beam's direction
+}
+
+// NewManifold checks the grid and finds the sources. Every row must be as
+// wide as the first: the sides are the same two columns all the way down.
+func NewManifold(lines []string) (*Manifold, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+	if len(lines[0]) == 0 {
+		return nil, fmt.Errorf("line 1: empty row")
+	}
+	for row, line := range lines {
+		if len(line) != len(lines[0]) {
+			return nil, fmt.Errorf("line %d: row is %d wide, want %d", row+1, len(line), len(lines[0]))
+		}
+	}
+	if err := validate(lines); err != nil {
+		return nil, err
+	}
+
+	m := &Manifold{grid: lines, width: len(lines[0])}
+	for row, line := range lines {
+		for col := range line {
+			switch line[col] {
+			case 'S':
+				m.sources = append(m.sources, Beam{Row: row, Col: col, Dir: Down})
+			case '/', '\\', 'v':
+				m.turns = true
+			}
+		}
+	}
+	if len(m.sources) == 0 {
+		return nil, fmt.Errorf("no starting position 'S' found")
+	}
+	return m, nil
+}
+
+// downward reports whether every beam only ever moves down: no tile turns it
+// and no side sends it back in. Then the rows are processed top to bottom.
+func (m *Manifold) downward() bool {
+	return !m.turns && m.Sides == SideExit
+}
+
+// Sweep is everything one pass through the manifold finds out.
+type Sweep struct {
+	// Splits counts the splitters reached by at least one beam (Part 1).
+	// Beams that meet merge into one, so a splitter splits at most once.
+	Splits int
+
+	rows, width int
+
+	// Per-cell particle counts, indexed row*width+col: how many timelines
+	// have the particle enter each cell, whichever way it is moving. Exactly
+	// one of small and large is set; large replaces small when some count
+	// overflows an int, and a nil entry in it is 0.
+	small []int
+	large []*big.Int
+
+	// Timelines that left the manifold, that were absorbed, and both (Part 2)
+	exits, absorbed, total struct {
+		small int
+		large *big.Int
+	}
+}
+
+// Sweep runs the beams through the manifold once, from the sources onward.
+// It returns a *LoopError if some beam can go round in circles forever.
+//
+// Algorithm: dynamic programming over rows with a dense count vector
+// Every timeline is a path from a source to an exit or absorber, and in the
+// puzzle's manifolds paths only move downward, so the number of timelines
+// reaching a cell is the sum of the counts of the cells that lead to it in the
+// row above. One vector of per-column counts, swept row by row, therefore
+// answers both parts at once:
+// - Part 1: a splitter is reached if its count is non-zero
+// - Part 2: the timelines are the counts that leave the grid or are absorbed
+//
+// Compared with recursion plus a memo keyed by "row,col" strings there are no
+// allocations per cell and no call stack as deep as the grid is tall.
+//
+// Mirrors, merges and sides that wrap or reflect can send beams sideways and
+// up, so rows no longer come one after another and loops become possible.
+// Only for such grids does Sweep build the graph of beam states (see analyze)
+// and run the same dynamic programming in its topological order instead.
+//
+// The counts double at every splitter, so a tall enough grid overflows an int.
+// The sweep starts with ints; when a row overflows, the counts so far move to
+// big.Int and the sweep carries on from that row, which costs more per cell
+// but cannot overflow.
+func (m *Manifold) Sweep() (*Sweep, error) {
+	if m.downward() {
+		return m.sweepRows(), nil
+	}
+
+	g, err := m.analyze()
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := sweepGraph(m, g, intCounts); ok {
+		return s, nil
+	}
+	s, _ := sweepGraph(m, g, bigCounts)
+	return s, nil
+}
+
+// counts abstracts the arithmetic the sweep needs over int or *big.Int. The
+// zero value of T is 0.
+type counts[T any] struct {
+	one    T
+	add    func(a, b T) (T, bool) // ok is false if the sum overflows
+	isZero func(T) bool
+	store  func(s *Sweep, t *tally[T], total T)
+}
+
+var intCounts = counts[int]{
+	one: 1,
+	add: func(a, b int) (int, bool) {
+		if a > math.MaxInt-b {
+			return 0, false
+		}
+		return a + b, true
+	},
+	isZero: func(n int) bool { return n == 0 },
+	store: func(s *Sweep, t *tally[int], total int) {
+		s.Splits, s.small = t.splits, t.cells
+		s.exits.small, s.absorbed.small, s.total.small = t.exits, t.absorbed, total
+	},
+}
+
+var bigCounts = counts[*big.Int]{
+	one: big.NewInt(1),
+	add: func(a, b *big.Int) (*big.Int, bool) {
+		sum := new(big.Int)
+		if a != nil {
+			sum.Set(a)
+		}
+		if b != nil {
+			sum.Add(sum, b)
+		}
+		return sum, true
+	},
+	isZero: func(n *big.Int) bool { return n == nil || n.Sign() == 0 },
+	store: func(s *Sweep, t *tally[*big.Int], total *big.Int) {
+		s.Splits, s.large = t.splits, t.cells
+		s.exits.large, s.absorbed.large, s.total.large = orZero(t.exits), orZero(t.absorbed), orZero(total)
+	},
+}
+
+// tally is what a sweep has counted so far, in ints or big.Ints.
+type tally[T any] struct {
+	cells           []T // intensities, indexed row*width+col
+	exits, absorbed T
+	splits          int
+}
+
+// addTo sets *dst to *dst + v, reporting false instead if that overflows.
+func (c counts[T]) addTo(dst *T, v T) bool {
+	sum, ok := c.add(*dst, v)
+	if ok {
+		*dst = sum
+	}
+	return ok
+}
+
+// sweepRows is Sweep for a grid where every beam moves down.
+func (m *Manifold) sweepRows() *Sweep {
+	small := tally[int]{cells: make([]int, len(m.grid)*m.width)}
+	current := make([]int, m.width)
+	row := 0
+	for ; row < len(m.grid); row++ {
+		next, ok := sweepRow(m, intCounts, &small, row, current)
+		if !ok {
+			break
+		}
+		current = next
+	}
+	if row == len(m.grid) {
+		if total, ok := finishRows(intCounts, &small, current); ok {
+			s := &Sweep{rows: len(m.grid), width: m.width}
+			intCounts.store(s, &small, total)
+			return s
+		}
+	}
+
+	// Something overflowed: carry on from this row with big.Int
+	large := tally[*big.Int]{
+		cells:    toBig(small.cells),
+		exits:    big.NewInt(int64(small.exits)),
+		absorbed: big.NewInt(int64(small.absorbed)),
+		splits:   small.splits,
+	}
+	bigCurrent := toBig(current)
+	for ; row < len(m.grid); row++ {
+		bigCurrent, _ = sweepRow(m, bigCounts, &large, row, bigCurrent)
+	}
+	total, _ := finishRows(bigCounts, &large, bigCurrent)
+	s := &Sweep{rows: len(m.grid), width: m.width}
+	bigCounts.store(s, &large, total)
+	return s
+}
+
+// sweepRow adds the sources on row to current, the counts entering it, and
+// returns the counts entering the next row. If a count overflows it returns
+// false and leaves t as it was, so the row can be done again with big.Int.
+func sweepRow[T any](m *Manifold, c counts[T], t *tally[T], row int, current []T) ([]T, bool) {
+	line := m.grid[row]
+	next := make([]T, m.width)
+	exits, absorbed, splits := t.exits, t.absorbed, 0
+
+	cloned := false
+	for _, b := range m.sources {
+		if b.Row != row {
+			continue
+		}
+		if !cloned {
+			current, cloned = slices.Clone(current), true // a failed row leaves current as it was
+		}
+		if !c.addTo(&current[b.Col], c.one) {
+			return nil, false
+		}
+	}
+	for col, v := range current {
+		if c.isZero(v) {
+			continue
+		}
+		ok := true
+		switch {
+		case line[col] == '^':
+			splits++
+			for _, to := range []int{col - 1, col + 1} {
+				if to < 0 || to >= m.width {
+					ok = ok && c.addTo(&exits, v) // split out of the side
+				} else {
+					ok = ok && c.addTo(&next[to], v)
+				}
+			}
+		case line[col] == '#':
+			ok = c.addTo(&absorbed, v)
+		default:
+			ok = c.addTo(&next[col], v)
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+
+	// The row went through; record it
+	copy(t.cells[row*m.width:], current)
+	t.exits, t.absorbed, t.splits = exits, absorbed, t.splits+splits
+	return next, true
+}
+
+// finishRows counts what leaves through the bottom and returns the total. Like
+// sweepRow, it leaves t as it was if a count overflows.
+func finishRows[T any](c counts[T], t *tally[T], below []T) (T, bool) {
+	exits := t.exits
+	for _, v := range below {
+		if !c.addTo(&exits, v) {
+			return exits, false
+		}
+	}
+	total := exits
+	if !c.addTo(&total, t.absorbed) {
+		return total, false
+	}
+	t.exits = exits
+	return total, true
+}
+
+func toBig(counts []int) []*big.Int {
+	large := make([]*big.Int, len(counts))
+	for i, n := range counts {
+		if n != 0 {
+			large[i] = big.NewInt(int64(n))
+		}
+	}
+	return large
+}
+
+// sweepGraph is Sweep over the graph of beam states, for grids where beams can
+// turn; ok is false if a count overflowed, and then it is done again with
+// big.Int. Each cell's intensity sums the states entering it.
+func sweepGraph[T any](m *Manifold, g *graph, c counts[T]) (*Sweep, bool) {
+	states := make([]T, len(g.next))
+	t := tally[T]{cells: make([]T, len(m.grid)*m.width), splits: g.splits}
+
+	for _, s := range g.sources {
+		c.addTo(&states[s], c.one) // cannot overflow: there are fewer sources than cells
+	}
+	for _, s := range g.order {
+		v := states[s]
+		if c.isZero(v) {
+			continue
+		}
+		if !c.addTo(&t.cells[s/4], v) {
+			return nil, false
+		}
+		for _, target := range g.next[s] {
+			ok := true
+			switch target {
+			case exitTarget:
+				ok = c.addTo(&t.exits, v)
+			case absorbTarget:
+				ok = c.addTo(&t.absorbed, v)
+			default:
+				ok = c.addTo(&states[target], v)
+			}
+			if !ok {
+				return nil, false
+			}
+		}
+	}
+
+	total := t.exits
+	if !c.addTo(&total, t.absorbed) {
+		return nil, false
+	}
+
+	s := &Sweep{rows: len(m.grid), width: m.width}
+	c.store(s, &t, total)
+	return s, true
+}
+
+func orZero(n *big.Int) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return n
+}
+
+// IsBig reports whether the counts needed big.Int.
+func (s *Sweep) IsBig() bool {
+	return s.large != nil
+}
+
+// Timelines returns the number of timelines (Part 2): every way the particles
+// can leave the manifold or be absorbed.
+func (s *Sweep) Timelines() *big.Int {
+	if s.large != nil {
+		return new(big.Int).Set(s.total.large)
+	}
+	return big.NewInt(int64(s.total.small))
+}
+
+// TimelinesInt returns the number of timelines as an int; ok is false if it
+// does not fit.
+func (s *Sweep) TimelinesInt() (n int, ok bool) {
+	return s.total.small, s.large == nil
+}
+
+// Exits returns the number of timelines that leave the manifold.
+func (s *Sweep) Exits() *big.Int {
+	if s.large != nil {
+		return new(big.Int).Set(s.exits.large)
+	}
+	return big.NewInt(int64(s.exits.small))
+}
+
+// Absorbed returns the number of timelines that end at an absorber, or at a
+// side when sides absorb.
+func (s *Sweep) Absorbed() *big.Int {
+	if s.large != nil {
+		return new(big.Int).Set(s.absorbed.large)
+	}
+	return big.NewInt(int64(s.absorbed.small))
+}
+
+// Intensity returns how many timelines have the particle enter (row, col),
+// whichever way it is moving: how bright the beam is there. It is 0 outside
+// the grid.
+func (s *Sweep) Intensity(row, col int) *big.Int {
+	total := new(big.Int)
+	if row < 0 || col < 0 || row >= s.rows || col >= s.width {
+		return total
+	}
+	if s.large != nil {
+		if n := s.large[row*s.width+col]; n != nil {
+			total.Set(n)
+		}
+		return total
+	}
+	return total.SetInt64(int64(s.small[row*s.width+col]))
+}
+
+// ColumnIntensity returns the total intensity of each column over all rows.
+func (s *Sweep) ColumnIntensity() []*big.Int {
+	totals := make([]*big.Int, s.width)
+	for col := range totals {
+		totals[col] = new(big.Int)
+		for row := 0; row < s.rows; row++ {
+			totals[col].Add(totals[col], s.Intensity(row, col))
+		}
+	}
+	return totals
+}
+
+// Rows returns the number of grid rows the sweep covers.
+func (s *Sweep) Rows() int {
+	return s.rows
+}
+
+// Width returns the number of columns the sweep covers.
+func (s *Sweep) Width() int {
+	return s.width
+}
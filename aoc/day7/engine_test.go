@@ -0,0 +1,199 @@
+package day7
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+const example = `.......S.......
+...............
+.......^.......
+...............
+......^.^......
+...............
+.....^.^.^.....
+...............
+....^.^...^....
+...............
+...^.^...^.^...
+...............
+..^...^.....^..
+...............
+.^.^.^.^.^...^.
+...............`
+
+func sweepOf(t *testing.T, grid string) *Sweep {
+	t.Helper()
+	return sweepLines(t, strings.Split(grid, "\n"))
+}
+
+func sweepLines(t *testing.T, lines []string) *Sweep {
+	t.Helper()
+	m, err := NewManifold(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := m.Sweep()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestExample(t *testing.T) {
+	s := sweepOf(t, example)
+	if s.Splits != 21 {
+		t.Errorf("splits = %d, want 21", s.Splits)
+	}
+	if n, ok := s.TimelinesInt(); !ok || n != 40 {
+		t.Errorf("timelines = %d (fits %v), want 40", n, ok)
+	}
+
+	// The beam enters below S at full intensity and splits on row 2
+	if s.Intensity(1, 7).Int64() != 1 || s.Intensity(3, 6).Int64() != 1 || s.Intensity(3, 7).Sign() != 0 {
+		t.Errorf("intensities around the first splitter: %v %v %v", s.Intensity(1, 7), s.Intensity(3, 6), s.Intensity(3, 7))
+	}
+	if s.Intensity(-1, 0).Sign() != 0 || s.Intensity(0, 99).Sign() != 0 {
+		t.Error("intensity outside the grid should be 0")
+	}
+}
+
+// recursiveTimelines is the memoised recursion the sweep replaced, kept as a
+// reference
+func recursiveTimelines(grid []string, row, col int, memo map[[2]int]int) int {
+	if row >= len(grid) || col < 0 || col >= len(grid[row]) {
+		return 1
+	}
+	if n, ok := memo[[2]int{row, col}]; ok {
+		return n
+	}
+	n := recursiveTimelines(grid, row+1, col, memo)
+	if grid[row][col] == '^' {
+		n = recursiveTimelines(grid, row+1, col-1, memo) + recursiveTimelines(grid, row+1, col+1, memo)
+	}
+	memo[[2]int{row, col}] = n
+	return n
+}
+
+// setSplits is Part 1 with a set of active beams
+func setSplits(grid []string, start int) int {
+	beams := map[int]bool{start: true}
+	splits := 0
+	for _, row := range grid {
+		next := map[int]bool{}
+		for col := range beams {
+			if col < 0 || col >= len(row) {
+				continue
+			}
+			if row[col] == '^' {
+				splits++
+				next[col-1], next[col+1] = true, true
+			} else {
+				next[col] = true
+			}
+		}
+		beams = next
+	}
+	return splits
+}
+
+func TestRandomGridsMatchReference(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for trial := 0; trial < 300; trial++ {
+		grid, width := make([]string, 1+r.Intn(20)), 1+r.Intn(12)
+		for i := range grid {
+			row := make([]byte, width)
+			for j := range row {
+				row[j] = '.'
+				if r.Intn(3) == 0 {
+					row[j] = '^'
+				}
+			}
+			grid[i] = string(row)
+		}
+		start := r.Intn(len(grid[0]))
+		row := []byte(grid[0])
+		row[start] = 'S'
+		grid[0] = string(row)
+
+		s := sweepLines(t, grid)
+		want := recursiveTimelines(grid, 0, start, map[[2]int]int{})
+		if n, ok := s.TimelinesInt(); !ok || n != want {
+			t.Fatalf("grid\n%s\ntimelines = %d, want %d", strings.Join(grid, "\n"), n, want)
+		}
+		if want := setSplits(grid, start); s.Splits != want {
+			t.Fatalf("grid\n%s\nsplits = %d, want %d", strings.Join(grid, "\n"), s.Splits, want)
+		}
+	}
+}
+
+// triangle builds a Pascal's triangle of splitters: every path splits at every
+// level and leaves through the bottom, so there are 2^levels timelines.
+func triangle(levels int) []string {
+	width := 2*levels + 1
+	blank := strings.Repeat(".", width)
+	grid := []string{blank[:levels] + "S" + blank[levels+1:]}
+	for k := 0; k < levels; k++ {
+		row := []byte(blank)
+		for col := levels - k; col <= levels+k; col += 2 {
+			row[col] = '^'
+		}
+		grid = append(grid, string(row), blank)
+	}
+	return grid
+}
+
+func TestOverflowSwitchesToBig(t *testing.T) {
+	if s := sweepLines(t, triangle(62)); s.IsBig() || s.Timelines().Cmp(new(big.Int).Lsh(big.NewInt(1), 62)) != 0 {
+		t.Errorf("62 levels: %v timelines (big %v), want 2^62 as an int", s.Timelines(), s.IsBig())
+	}
+
+	// Every cell of 63 levels fits in an int, but the total through the bottom
+	// is 2^63
+	if s := sweepLines(t, triangle(63)); !s.IsBig() || s.Timelines().Cmp(new(big.Int).Lsh(big.NewInt(1), 63)) != 0 {
+		t.Errorf("63 levels: %v timelines (big %v), want 2^63", s.Timelines(), s.IsBig())
+	}
+
+	s := sweepLines(t, triangle(100))
+	want := new(big.Int).Lsh(big.NewInt(1), 100)
+	if !s.IsBig() || s.Timelines().Cmp(want) != 0 {
+		t.Errorf("100 levels: %v timelines (big %v), want %v", s.Timelines(), s.IsBig(), want)
+	}
+	if _, ok := s.TimelinesInt(); ok {
+		t.Error("TimelinesInt should report that 2^100 does not fit")
+	}
+	if s.Splits != 100*101/2 {
+		t.Errorf("splits = %d, want %d", s.Splits, 100*101/2)
+	}
+
+	// The bottom row of the triangle is row 100 of Pascal's triangle
+	bottom := s.Intensity(len(triangle(100))-1, 100)
+	if want := new(big.Int).Binomial(100, 50); bottom.Cmp(want) != 0 {
+		t.Errorf("centre of the bottom row = %v, want C(100, 50) = %v", bottom, want)
+	}
+}
+
+func TestColumnIntensity(t *testing.T) {
+	s := sweepOf(t, "S.\n..\n^.")
+	got := s.ColumnIntensity()
+	if len(got) != 2 || got[0].Int64() != 3 || got[1].Sign() != 0 {
+		t.Errorf("column intensity = %v, want [3 0]", got)
+	}
+}
+
+func TestNewManifoldErrors(t *testing.T) {
+	if _, err := NewManifold(nil); err == nil {
+		t.Error("empty input should be rejected")
+	}
+	if _, err := NewManifold([]string{"...", ".^."}); err == nil {
+		t.Error("input without S should be rejected")
+	}
+	if _, err := NewManifold([]string{"", "S"}); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("empty row: got %v, want an error at line 1", err)
+	}
+	if _, err := NewManifold([]string{"S..", ".^", "..."}); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ragged rows: got %v, want an error at line 2", err)
+	}
+}
@@ -9,72 +9,29 @@ import "fmt"
 // We need to count the total number of unique timelines (complete paths through the manifold).
 //
 // Algorithm:
-// - Use recursive DFS to explore all possible paths from S to exit
-// - When particle hits a splitter: branch into left and right timelines
-// - When particle hits empty space: continue straight down
-// - When particle exits the grid: count as 1 timeline
-// - Use memoization to cache results for positions we've seen before
+// - Sweep the manifold top to bottom, keeping how many timelines reach each column
+// - When particle hits a splitter: its count goes to both the left and right columns
+// - When particle hits empty space: its count continues straight down
+// - When particle exits the grid: its count is added to the timelines
+// - See Manifold.Sweep; the same pass answers Part 1
 func Part2(inputPath string) (int, error) {
 	lines, err := FromFile(inputPath)
 	if err != nil {
 		return 0, fmt.Errorf("loading input: %w", err)
 	}
 
-	if len(lines) == 0 {
-		return 0, fmt.Errorf("empty input")
+	m, err := NewManifold(lines)
+	if err != nil {
+		return 0, err
 	}
 
-	// Find starting position (S)
-	startCol := -1
-	for i, ch := range lines[0] {
-		if ch == 'S' {
-			startCol = i
-			break
-		}
+	s, err := m.Sweep()
+	if err != nil {
+		return 0, err
 	}
-	if startCol == -1 {
-		return 0, fmt.Errorf("no starting position 'S' found")
+	timelines, ok := s.TimelinesInt()
+	if !ok {
+		return 0, fmt.Errorf("%v timelines do not fit in an int", s.Timelines())
 	}
-
-	// Count all possible timelines using memoized recursion
-	memo := make(map[string]int)
-	timelines := countTimelines(lines, 0, startCol, memo)
-
 	return timelines, nil
 }
-
-// countTimelines recursively counts the number of unique paths (timelines)
-// from position (row, col) to exiting the manifold.
-func countTimelines(grid []string, row, col int, memo map[string]int) int {
-	// Base case: exited the bottom of the manifold
-	if row >= len(grid) {
-		return 1 // This path is one complete timeline
-	}
-
-	// Out of bounds horizontally - particle exits
-	if col < 0 || col >= len(grid[row]) {
-		return 1 // Exited sideways, counts as one timeline
-	}
-
-	// Check memoization cache
-	key := fmt.Sprintf("%d,%d", row, col)
-	if val, ok := memo[key]; ok {
-		return val
-	}
-
-	var count int
-	ch := grid[row][col]
-
-	if ch == '^' {
-		// Hit a splitter - reality splits into two timelines
-		leftTimelines := countTimelines(grid, row+1, col-1, memo)
-		rightTimelines := countTimelines(grid, row+1, col+1, memo)
-		count = leftTimelines + rightTimelines
-	} else {
-		// Empty space or S - particle continues straight down
-		count = countTimelines(grid, row+1, col, memo)
-	}
-
-	memo[key] = count
-	return count
-}
@@ -0,0 +1,286 @@
+package day7
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tiles a manifold can contain. The puzzle only uses the first three.
+//
+//	'.' empty space   'S' a source: a particle enters here moving down
+//	'^' splitter      the beam stops; new beams go down from both sides of it
+//	'/' mirror        down turns left, right turns up (and back)
+//	'\' mirror        down turns right, left turns up (and back)
+//	'#' absorber      the beam ends here
+//	'v' merge         every beam leaves moving down, whichever way it came in
+//
+// A splitter treats every beam alike, so a beam a mirror sends sideways into
+// it is split downward too. Beams crossing an 'S' carry on as over '.'.
+const Tiles = ".S^/\\#v"
+
+// Direction is the way a beam is moving.
+type Direction int
+
+const (
+	Down Direction = iota
+	Up
+	Left
+	Right
+)
+
+func (d Direction) String() string {
+	return [...]string{"down", "up", "left", "right"}[d]
+}
+
+var (
+	moves     = [...][2]int{Down: {1, 0}, Up: {-1, 0}, Left: {0, -1}, Right: {0, 1}}
+	slash     = [...]Direction{Down: Left, Up: Right, Left: Down, Right: Up}
+	backslash = [...]Direction{Down: Right, Up: Left, Left: Up, Right: Down}
+)
+
+// Side is what happens to a beam that leaves a row through its left or right
+// end. Beams leaving through the top or bottom always exit.
+type Side int
+
+const (
+	SideExit    Side = iota // the timeline leaves the manifold, as in the puzzle
+	SideWrap                // the beam comes back in at the other end of the row
+	SideAbsorb              // the timeline ends, as at an absorber
+	SideReflect             // the beam bounces back into the edge cell
+)
+
+func (s Side) String() string {
+	return [...]string{"exit", "wrap", "absorb", "reflect"}[s]
+}
+
+// ParseSide reads a Side from its name, as printed by String.
+func ParseSide(name string) (Side, error) {
+	for s := SideExit; s <= SideReflect; s++ {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown side behaviour %q (want exit, wrap, absorb or reflect)", name)
+}
+
+// Beam is a beam entering a cell: 0-based row and column, and its direction.
+type Beam struct {
+	Row, Col int
+	Dir      Direction
+}
+
+func (b Beam) String() string {
+	return fmt.Sprintf("line %d, column %d moving %v", b.Row+1, b.Col+1, b.Dir)
+}
+
+// ErrLoop is wrapped by LoopError.
+var ErrLoop = errors.New("beam loops forever")
+
+// LoopError reports a cycle of beam states. A beam caught in one never
+// leaves, so neither the timelines through it nor their number are finite.
+type LoopError struct {
+	Cycle []Beam // each beam leads to the next, and the last to the first
+}
+
+func (e *LoopError) Error() string {
+	return fmt.Sprintf("%v: %d beam states from %v", ErrLoop, len(e.Cycle), e.Cycle[0])
+}
+
+func (e *LoopError) Unwrap() error {
+	return ErrLoop
+}
+
+// Targets of a beam state that are not another state.
+const (
+	exitTarget   = -1 // left the manifold
+	absorbTarget = -2 // ended inside it
+)
+
+// graph is how beam states lead to one another, restricted to the states some
+// source reaches. A state is a Beam numbered ((Row*width)+Col)*4+Dir.
+type graph struct {
+	next    [][]int // next[s]: the states or targets state s leads to
+	sources []int
+	order   []int // every reachable state, each after all states leading to it
+	splits  int   // splitters reached
+}
+
+func (m *Manifold) state(b Beam) int {
+	return (b.Row*m.width+b.Col)*4 + int(b.Dir)
+}
+
+func (m *Manifold) beam(state int) Beam {
+	cell := state / 4
+	return Beam{Row: cell / m.width, Col: cell % m.width, Dir: Direction(state % 4)}
+}
+
+// target resolves a beam about to enter row, col: the state it becomes, or
+// where it ends if it leaves the manifold.
+func (m *Manifold) target(row, col int, dir Direction) int {
+	if row < 0 || row >= len(m.grid) {
+		return exitTarget
+	}
+	n := m.width
+	if col < 0 || col >= n {
+		switch m.Sides {
+		case SideExit:
+			return exitTarget
+		case SideAbsorb:
+			return absorbTarget
+		case SideWrap:
+			col = (col%n + n) % n
+		case SideReflect:
+			col = min(max(col, 0), n-1)
+			if dir == Left || dir == Right {
+				dir ^= 1 // Left <-> Right
+			}
+		}
+	}
+	return m.state(Beam{Row: row, Col: col, Dir: dir})
+}
+
+// successors returns what the beam state leads to: one state or target, or
+// two for a splitter.
+func (m *Manifold) successors(state int) []int {
+	b := m.beam(state)
+	dir := b.Dir
+	switch m.grid[b.Row][b.Col] {
+	case '^':
+		return []int{m.target(b.Row+1, b.Col-1, Down), m.target(b.Row+1, b.Col+1, Down)}
+	case '#':
+		return []int{absorbTarget}
+	case '/':
+		dir = slash[dir]
+	case '\\':
+		dir = backslash[dir]
+	case 'v':
+		dir = Down
+	}
+	return []int{m.target(b.Row+moves[dir][0], b.Col+moves[dir][1], dir)}
+}
+
+// analyze builds the graph of beam states reachable from the sources, in an
+// order in which every state comes after the states leading to it, or
+// reports a loop if there is no such order.
+//
+// Algorithm: breadth-first search, then Kahn's topological sort
+// The search finds the reachable states and how many edges lead into each.
+// Kahn's algorithm then repeatedly takes a state nothing unprocessed leads
+// into. States left over when it stops lie on or after a cycle; each has a
+// leftover predecessor, so following predecessors back must repeat a state,
+// and the states between the two visits form the cycle.
+func (m *Manifold) analyze() (*graph, error) {
+	g := &graph{next: make([][]int, len(m.grid)*m.width*4)}
+	seen := make([]bool, len(g.next))
+	indegree := make([]int, len(g.next))
+	split := make([]bool, len(m.grid)*m.width) // by cell
+
+	var reached []int
+	visit := func(s int) {
+		if !seen[s] {
+			seen[s] = true
+			reached = append(reached, s)
+		}
+	}
+	for _, b := range m.sources {
+		s := m.state(b)
+		g.sources = append(g.sources, s)
+		visit(s)
+	}
+	for i := 0; i < len(reached); i++ {
+		s := reached[i]
+		if b := m.beam(s); m.grid[b.Row][b.Col] == '^' && !split[s/4] {
+			split[s/4] = true
+			g.splits++
+		}
+		g.next[s] = m.successors(s)
+		for _, t := range g.next[s] {
+			if t >= 0 {
+				indegree[t]++
+				visit(t)
+			}
+		}
+	}
+
+	for _, s := range reached {
+		if indegree[s] == 0 {
+			g.order = append(g.order, s)
+		}
+	}
+	for i := 0; i < len(g.order); i++ {
+		for _, t := range g.next[g.order[i]] {
+			if t >= 0 {
+				if indegree[t]--; indegree[t] == 0 {
+					g.order = append(g.order, t)
+				}
+			}
+		}
+	}
+	if len(g.order) < len(reached) {
+		return nil, m.loop(g, reached, indegree)
+	}
+	return g, nil
+}
+
+// loop finds a cycle among the states Kahn's algorithm left over: those whose
+// indegree is still positive.
+func (m *Manifold) loop(g *graph, reached, indegree []int) *LoopError {
+	pred := make(map[int]int)
+	for _, s := range reached {
+		if indegree[s] == 0 {
+			continue
+		}
+		for _, t := range g.next[s] {
+			if t >= 0 && indegree[t] > 0 {
+				pred[t] = s
+			}
+		}
+	}
+
+	// Walk back until a state repeats
+	var walk []int
+	at := make(map[int]int)
+	s := reached[0]
+	for _, r := range reached {
+		if indegree[r] > 0 {
+			s = r
+			break
+		}
+	}
+	for {
+		if i, ok := at[s]; ok {
+			walk = walk[i:]
+			break
+		}
+		at[s] = len(walk)
+		walk = append(walk, s)
+		s = pred[s]
+	}
+
+	// walk runs against the beams; report the cycle the way they go, from
+	// its first state in reading order
+	first := 0
+	for i, s := range walk {
+		if s < walk[first] {
+			first = i
+		}
+	}
+	cycle := make([]Beam, len(walk))
+	for i := range cycle {
+		cycle[i] = m.beam(walk[(first-i+len(walk))%len(walk)])
+	}
+	return &LoopError{Cycle: cycle}
+}
+
+// validate rejects characters that are not Tiles.
+func validate(lines []string) error {
+	for row, line := range lines {
+		if col := strings.IndexFunc(line, func(r rune) bool { return !strings.ContainsRune(Tiles, r) }); col >= 0 {
+			r, _ := utf8.DecodeRuneInString(line[col:])
+			return fmt.Errorf("line %d, column %d: unknown tile %q", row+1, col+1, r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package day7
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func sweepWith(t *testing.T, grid string, sides Side) *Sweep {
+	t.Helper()
+	m, err := NewManifold(strings.Split(grid, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Sides = sides
+	s, err := m.Sweep()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestTiles(t *testing.T) {
+	for _, tc := range []struct {
+		name                   string
+		grid                   string
+		sides                  Side
+		splits, exits, absorbs int64
+	}{
+		{"mirror exits the side", "S\n/", SideExit, 0, 1, 0},
+		{"mirror into an absorbing side", "S\n/", SideAbsorb, 0, 0, 1},
+		{"mirror wraps back onto itself", "S\n/", SideWrap, 0, 1, 0},
+		{"mirror reflects up and out of the top", "S\n/", SideReflect, 0, 1, 0},
+		{"backslash mirror", "S.\n\\.\n..", SideExit, 0, 1, 0},
+		{"absorber", "S\n#", SideExit, 0, 0, 1},
+		{"merge turns a sideways beam down", "S..\n\\v.\n...", SideExit, 0, 1, 0},
+		{"splitter hit from the side", "S.\n\\^\n..", SideExit, 1, 2, 0},
+		{"two sources", "S.S\n...", SideExit, 0, 2, 0},
+		{"source on a beam's path", "S\nS\n.", SideExit, 0, 2, 0},
+		{"split off an absorbing side", "S.\n^.\n..", SideAbsorb, 1, 1, 1},
+		{"split wrapped onto the same cell", "S.\n^.\n..", SideWrap, 1, 2, 0},
+		{"split reflected off the side", "S.\n^.\n..", SideReflect, 1, 2, 0},
+	} {
+		s := sweepWith(t, tc.grid, tc.sides)
+		if int64(s.Splits) != tc.splits || s.Exits().Int64() != tc.exits || s.Absorbed().Int64() != tc.absorbs {
+			t.Errorf("%s (sides %v): %d splits, %v exits, %v absorbed; want %d, %d, %d",
+				tc.name, tc.sides, s.Splits, s.Exits(), s.Absorbed(), tc.splits, tc.exits, tc.absorbs)
+		}
+		if want := tc.exits + tc.absorbs; s.Timelines().Int64() != want {
+			t.Errorf("%s: %v timelines, want %d", tc.name, s.Timelines(), want)
+		}
+	}
+}
+
+func TestSidesMoveTheBeam(t *testing.T) {
+	// Both halves of the split land on column 1 when wrapped...
+	if got := sweepWith(t, "S.\n^.\n..", SideWrap).Intensity(2, 1).Int64(); got != 2 {
+		t.Errorf("wrapped: intensity %d below the splitter's right, want 2", got)
+	}
+	// ...and one on each column when reflected
+	s := sweepWith(t, "S.\n^.\n..", SideReflect)
+	if s.Intensity(2, 0).Int64() != 1 || s.Intensity(2, 1).Int64() != 1 {
+		t.Errorf("reflected: intensities %v and %v below the splitter, want 1 and 1", s.Intensity(2, 0), s.Intensity(2, 1))
+	}
+	// The merge sends the mirrored beam down column 1
+	if got := sweepWith(t, "S..\n\\v.\n...", SideExit).Intensity(2, 1).Int64(); got != 1 {
+		t.Errorf("merge: intensity %d below the merge, want 1", got)
+	}
+}
+
+// loopGrid has a rectangle of mirrors the beam joins through the merge:
+// up column 0, right along row 1, down column 1, left along row 3.
+const loopGrid = "...S\n" +
+	"/\\..\n" +
+	".v./\n" +
+	"\\/.."
+
+func TestLoopIsReported(t *testing.T) {
+	m, err := NewManifold(strings.Split(loopGrid, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Sweep()
+	var loop *LoopError
+	if !errors.Is(err, ErrLoop) || !errors.As(err, &loop) {
+		t.Fatalf("got %v, want a LoopError", err)
+	}
+
+	want := []Beam{{1, 0, Up}, {1, 1, Right}, {2, 1, Down}, {3, 1, Down}, {3, 0, Left}, {2, 0, Up}}
+	if len(loop.Cycle) != len(want) {
+		t.Fatalf("cycle %v, want %v", loop.Cycle, want)
+	}
+	for i := range want {
+		if loop.Cycle[i] != want[i] {
+			t.Fatalf("cycle %v, want %v", loop.Cycle, want)
+		}
+	}
+	if got := err.Error(); got != "beam loops forever: 6 beam states from line 2, column 1 moving up" {
+		t.Errorf("message %q", got)
+	}
+
+}
+
+func TestUnknownTile(t *testing.T) {
+	if _, err := NewManifold([]string{"S.", ".x"}); err == nil || !strings.Contains(err.Error(), "line 2, column 2") {
+		t.Errorf("got %v, want an error at line 2, column 2", err)
+	}
+}
+
+// countPaths is a reference: depth-first search over beam states with a memo,
+// reporting ok false if it finds a state on its own stack.
+func countPaths(m *Manifold, state int, memo map[int]int, onStack map[int]bool) (n int, ok bool) {
+	if n, done := memo[state]; done {
+		return n, true
+	}
+	if onStack[state] {
+		return 0, false
+	}
+	onStack[state] = true
+	for _, t := range m.successors(state) {
+		if t < 0 {
+			n++
+			continue
+		}
+		k, ok := countPaths(m, t, memo, onStack)
+		if !ok {
+			return 0, false
+		}
+		n += k
+	}
+	onStack[state] = false
+	memo[state] = n
+	return n, true
+}
+
+func TestRandomTilesMatchReference(t *testing.T) {
+	r := rand.New(rand.NewSource(49))
+	loops := 0
+	for trial := 0; trial < 2000; trial++ {
+		grid, width := make([]string, 1+r.Intn(8)), 1+r.Intn(8)
+		for i := range grid {
+			row := make([]byte, width)
+			for j := range row {
+				row[j] = "......^^/\\#vS"[r.Intn(13)]
+			}
+			grid[i] = string(row)
+		}
+		grid[0] = "S" + grid[0][1:]
+
+		m, err := NewManifold(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Sides = Side(r.Intn(4))
+
+		want, acyclic := 0, true
+		memo, onStack := map[int]int{}, map[int]bool{}
+		for _, b := range m.sources {
+			n, ok := countPaths(m, m.state(b), memo, onStack)
+			want, acyclic = want+n, acyclic && ok
+		}
+
+		s, err := m.Sweep()
+		if !acyclic {
+			loops++
+			if !errors.Is(err, ErrLoop) {
+				t.Fatalf("sides %v, grid\n%s\ngot %v, want a loop", m.Sides, strings.Join(grid, "\n"), err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("sides %v, grid\n%s\n%v", m.Sides, strings.Join(grid, "\n"), err)
+		}
+		if n, ok := s.TimelinesInt(); !ok || n != want {
+			t.Fatalf("sides %v, grid\n%s\n%d timelines, want %d", m.Sides, strings.Join(grid, "\n"), n, want)
+		}
+	}
+	if loops == 0 {
+		t.Error("no random grid had a loop; the test is not covering them")
+	}
+}
+
+// sameSweep compares two sweeps' counts, cell by cell
+func sameSweep(a, b *Sweep) bool {
+	if a.Splits != b.Splits || a.Exits().Cmp(b.Exits()) != 0 || a.Absorbed().Cmp(b.Absorbed()) != 0 ||
+		a.Timelines().Cmp(b.Timelines()) != 0 || a.Rows() != b.Rows() || a.Width() != b.Width() {
+		return false
+	}
+	for row := 0; row < a.Rows(); row++ {
+		for col := 0; col < a.Width(); col++ {
+			if a.Intensity(row, col).Cmp(b.Intensity(row, col)) != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// graphSweep forces the beam-state graph that Sweep only builds when beams
+// can turn
+func graphSweep(t *testing.T, m *Manifold) *Sweep {
+	t.Helper()
+	g, err := m.analyze()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := sweepGraph(m, g, intCounts); ok {
+		return s
+	}
+	s, _ := sweepGraph(m, g, bigCounts)
+	return s
+}
+
+func TestRowSweepMatchesGraph(t *testing.T) {
+	r := rand.New(rand.NewSource(48))
+	for trial := 0; trial < 500; trial++ {
+		grid, width := make([]string, 1+r.Intn(10)), 1+r.Intn(10)
+		for i := range grid {
+			row := make([]byte, width)
+			for j := range row {
+				row[j] = "....^^#S"[r.Intn(8)]
+			}
+			grid[i] = string(row)
+		}
+		grid[0] = "S" + grid[0][1:]
+
+		m, err := NewManifold(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !m.downward() {
+			t.Fatal("grid without turning tiles should be swept by rows")
+		}
+		if rows, graph := m.sweepRows(), graphSweep(t, m); !sameSweep(rows, graph) {
+			t.Fatalf("grid\n%s\nrow sweep and graph disagree: %v vs %v timelines, %d vs %d splits",
+				strings.Join(grid, "\n"), rows.Timelines(), graph.Timelines(), rows.Splits, graph.Splits)
+		}
+	}
+
+	// Overflowing partway down, with a source on every row
+	grid := triangle(70)
+	for i := 1; i < len(grid); i++ {
+		grid[i] = "S" + grid[i][1:]
+	}
+	m, err := NewManifold(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := m.sweepRows()
+	if !rows.IsBig() || !sameSweep(rows, graphSweep(t, m)) {
+		t.Errorf("overflowing row sweep (big %v) disagrees with the graph", rows.IsBig())
+	}
+
+	// Turning tiles and side modes other than exit need the graph
+	if m, _ := NewManifold([]string{"S", "/"}); m.downward() {
+		t.Error("a mirror should need the graph")
+	}
+	m, _ = NewManifold([]string{"S", "^"})
+	if m.Sides = SideWrap; m.downward() {
+		t.Error("wrapping sides should need the graph")
+	}
+}
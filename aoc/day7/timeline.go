@@ -0,0 +1,162 @@
+package day7
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+)
+
+// Timeline is one complete path of the particle through the manifold.
+type Timeline struct {
+	Source   int    // which 'S' it starts from, counting in reading order from 0
+	Choices  string // 'L' or 'R' for each splitter on the way
+	Path     []Beam // every beam state the particle enters, in order
+	Absorbed bool   // it ends at an absorber or absorbing side, not outside
+}
+
+// TimelineIndex numbers every timeline of a manifold so that any one of them
+// can be found without listing the others.
+//
+// Timelines are ordered by source, then by their choices read as words over
+// L < R: the order a depth-first search taking the left beam first would meet
+// them in. A timeline's choices decide it, and two timelines from a splitter
+// first differ at that splitter, so this is a total order.
+type TimelineIndex struct {
+	m     *Manifold
+	g     *graph
+	ways  []*big.Int // ways[s]: timelines from beam state s onward, nil if unreachable
+	total *big.Int
+}
+
+// Timelines indexes the manifold's timelines, or returns a *LoopError.
+//
+// Algorithm: counting paths to the end in reverse topological order
+// Sweep counts the timelines reaching each beam state; this counts those
+// leaving it, ways[s]. Processed last to first in the order analyze finds,
+// every state comes after the states it leads to, so ways[s] is the sum of
+// their counts, each exit or absorption counting 1. Knowing how many
+// timelines lie down each branch is what lets At skip whole subtrees.
+//
+// The counts are big.Ints from the start, since At takes a big.Int anyway.
+// Only splitters add; every other state has the same count as its successor
+// and shares its big.Int, so the memory used grows with the splitters reached
+// and the digits of their counts, not with the length of the paths.
+func (m *Manifold) Timelines() (*TimelineIndex, error) {
+	g, err := m.analyze()
+	if err != nil {
+		return nil, err
+	}
+
+	one := big.NewInt(1)
+	x := &TimelineIndex{m: m, g: g, ways: make([]*big.Int, len(g.next)), total: new(big.Int)}
+	count := func(target int) *big.Int {
+		if target < 0 {
+			return one
+		}
+		return x.ways[target]
+	}
+	for i := len(g.order) - 1; i >= 0; i-- {
+		s := g.order[i]
+		if next := g.next[s]; len(next) == 1 {
+			x.ways[s] = count(next[0])
+		} else {
+			x.ways[s] = new(big.Int).Add(count(next[0]), count(next[1]))
+		}
+	}
+	for _, s := range g.sources {
+		x.total.Add(x.total, x.ways[s])
+	}
+	return x, nil
+}
+
+// Count returns the number of timelines; it is Sweep's Timelines.
+func (x *TimelineIndex) Count() *big.Int {
+	return new(big.Int).Set(x.total)
+}
+
+// At returns timeline k, counting from 0 in the order described on
+// TimelineIndex.
+//
+// The walk follows the particle from its source. At each splitter, if k is
+// less than the number of timelines down the left beam it goes left;
+// otherwise it subtracts them and goes right. So it takes time proportional
+// to the path's length, however many timelines there are.
+func (x *TimelineIndex) At(k *big.Int) (*Timeline, error) {
+	if k.Sign() < 0 || k.Cmp(x.total) >= 0 {
+		return nil, fmt.Errorf("timeline %v out of range: there are %v", k, x.total)
+	}
+	k = new(big.Int).Set(k)
+
+	t := &Timeline{}
+	s := x.g.sources[0]
+	for i, source := range x.g.sources {
+		if k.Cmp(x.ways[source]) < 0 {
+			t.Source, s = i, source
+			break
+		}
+		k.Sub(k, x.ways[source])
+	}
+
+	var choices []byte
+	for {
+		t.Path = append(t.Path, x.m.beam(s))
+		next := x.g.next[s]
+		target := next[0]
+		if len(next) == 2 {
+			left := big.NewInt(1)
+			if next[0] >= 0 {
+				left = x.ways[next[0]]
+			}
+			if k.Cmp(left) < 0 {
+				choices = append(choices, 'L')
+			} else {
+				choices = append(choices, 'R')
+				k.Sub(k, left)
+				target = next[1]
+			}
+		}
+		if target < 0 {
+			t.Absorbed = target == absorbTarget
+			break
+		}
+		s = target
+	}
+	t.Choices = string(choices)
+	return t, nil
+}
+
+// Sample returns a timeline chosen uniformly at random: every timeline is
+// equally likely, however unevenly they are spread over the grid. A rand.Rand
+// with a fixed seed always gives the same samples.
+func (x *TimelineIndex) Sample(r *rand.Rand) *Timeline {
+	t, _ := x.At(new(big.Int).Rand(r, x.total)) // always in range
+	return t
+}
+
+// Render draws the timeline's path over the grid: '|' where the particle
+// moves down or up, '-' where it moves left or right, and '+' where it does
+// both. Tiles other than empty space are left as they are.
+func (m *Manifold) Render(t *Timeline) []string {
+	rows := make([][]byte, len(m.grid))
+	for i, line := range m.grid {
+		rows[i] = []byte(line)
+	}
+	for _, b := range t.Path {
+		mark := byte('|')
+		if b.Dir == Left || b.Dir == Right {
+			mark = '-'
+		}
+		switch c := &rows[b.Row][b.Col]; {
+		case *c == '.':
+			*c = mark
+		case *c == '|' && mark == '-', *c == '-' && mark == '|':
+			*c = '+'
+		}
+	}
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = string(row)
+	}
+	return lines
+}
@@ -0,0 +1,207 @@
+package day7
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func indexOf(t *testing.T, m *Manifold) *TimelineIndex {
+	t.Helper()
+	x, err := m.Timelines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+// enumerate lists every timeline's choices by depth-first search, left first
+func enumerate(m *Manifold, state int, prefix string, out *[]string) {
+	next := m.successors(state)
+	for i, target := range next {
+		choices := prefix
+		if len(next) == 2 {
+			choices += string("LR"[i])
+		}
+		if target < 0 {
+			*out = append(*out, choices)
+		} else {
+			enumerate(m, target, choices, out)
+		}
+	}
+}
+
+func checkEnumeration(t *testing.T, m *Manifold) {
+	t.Helper()
+	var want []string
+	for _, b := range m.sources {
+		enumerate(m, m.state(b), "", &want)
+	}
+
+	x := indexOf(t, m)
+	if x.Count().Cmp(big.NewInt(int64(len(want)))) != 0 {
+		t.Fatalf("grid\n%s\ncount %v, want %d", strings.Join(m.grid, "\n"), x.Count(), len(want))
+	}
+	for k := range want {
+		tl, err := x.At(big.NewInt(int64(k)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tl.Choices != want[k] {
+			t.Fatalf("grid\n%s\ntimeline %d chooses %q, want %q", strings.Join(m.grid, "\n"), k, tl.Choices, want[k])
+		}
+	}
+}
+
+func TestExampleTimelines(t *testing.T) {
+	m, err := NewManifold(strings.Split(example, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEnumeration(t, m)
+
+	x := indexOf(t, m)
+	first, _ := x.At(big.NewInt(0))
+	last, _ := x.At(big.NewInt(39))
+	if first.Choices != "LLLLLLL" || last.Choices != "RRRRRRR" {
+		t.Errorf("first %q, last %q", first.Choices, last.Choices)
+	}
+	if _, err := x.At(big.NewInt(40)); err == nil {
+		t.Error("timeline 40 of 40 should be out of range")
+	}
+	if _, err := x.At(big.NewInt(-1)); err == nil {
+		t.Error("timeline -1 should be out of range")
+	}
+}
+
+func TestRandomTimelinesInOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(50))
+	for trial := 0; trial < 500; trial++ {
+		grid, width := make([]string, 1+r.Intn(8)), 1+r.Intn(8)
+		for i := range grid {
+			row := make([]byte, width)
+			for j := range row {
+				row[j] = "......^^/\\#vS"[r.Intn(13)]
+			}
+			grid[i] = string(row)
+		}
+		grid[0] = "S" + grid[0][1:]
+
+		m, err := NewManifold(grid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Sides = Side(r.Intn(4))
+		if _, err := m.Timelines(); err != nil {
+			if !errors.Is(err, ErrLoop) {
+				t.Fatal(err)
+			}
+			continue
+		}
+		checkEnumeration(t, m)
+	}
+}
+
+func TestAstronomicalTimelines(t *testing.T) {
+	const levels = 300
+	m, err := NewManifold(triangle(levels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := indexOf(t, m)
+	if want := new(big.Int).Lsh(big.NewInt(1), levels); x.Count().Cmp(want) != 0 {
+		t.Fatalf("count %v, want 2^%d", x.Count(), levels)
+	}
+
+	// Every timeline splits at every level, so timeline k chooses the bits
+	// of k, most significant first, with L for 0 and R for 1
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		k := new(big.Int).Rand(r, x.Count())
+		tl, err := x.At(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bits := strings.NewReplacer("0", "L", "1", "R").Replace(k.Text(2))
+		if want := strings.Repeat("L", levels-len(bits)) + bits; tl.Choices != want {
+			t.Fatalf("timeline %v chooses %s, want %s", k, tl.Choices, want)
+		}
+		if len(tl.Path) != len(triangle(levels)) {
+			t.Fatalf("timeline %v enters %d cells, want one per row", k, len(tl.Path))
+		}
+	}
+}
+
+func TestSampleIsSeededAndUniform(t *testing.T) {
+	m, err := NewManifold(strings.Split(example, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := indexOf(t, m)
+
+	a, b := rand.New(rand.NewSource(99)), rand.New(rand.NewSource(99))
+	for i := 0; i < 10; i++ {
+		if x.Sample(a).Choices != x.Sample(b).Choices {
+			t.Fatal("the same seed should give the same samples")
+		}
+	}
+
+	// 40 timelines, 1000 expected each; a walk that flipped a coin at every
+	// splitter would favour the short timelines instead
+	seen := make(map[string]int)
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 40000; i++ {
+		seen[x.Sample(r).Choices]++
+	}
+	if len(seen) != 40 {
+		t.Errorf("sampled %d distinct timelines, want 40", len(seen))
+	}
+	for choices, n := range seen {
+		if n < 800 || n > 1200 {
+			t.Errorf("timeline %s sampled %d times, want about 1000", choices, n)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	m, err := NewManifold([]string{"..S..", ".....", "..^..", "....."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := indexOf(t, m)
+	for k, want := range []string{
+		"..S..\n..|..\n..^..\n.|...",
+		"..S..\n..|..\n..^..\n...|.",
+	} {
+		tl, _ := x.At(big.NewInt(int64(k)))
+		if got := strings.Join(m.Render(tl), "\n"); got != want {
+			t.Errorf("timeline %d rendered as\n%s\nwant\n%s", k, got, want)
+		}
+	}
+
+	// Mirrors bring the beam back across its own path
+	m, err = NewManifold([]string{".S..", "....", "/.\\.", ".\\/."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl, _ := indexOf(t, m).At(big.NewInt(0))
+	want := ".S..\n.|..\n/+\\.\n|\\/."
+	if got := strings.Join(m.Render(tl), "\n"); got != want {
+		t.Errorf("crossing path rendered as\n%s\nwant\n%s", got, want)
+	}
+	if tl.Absorbed || tl.Choices != "" || len(tl.Path) != 9 {
+		t.Errorf("crossing path: %+v", tl)
+	}
+}
+
+func TestTimelinesReportLoops(t *testing.T) {
+	m, err := NewManifold(strings.Split(loopGrid, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Timelines(); !errors.Is(err, ErrLoop) {
+		t.Errorf("got %v, want a loop", err)
+	}
+}
@@ -0,0 +1,141 @@
+package day9
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"adv2025/aoc/difftest"
+)
+
+// histogram describes a rectilinear polygon: a row of columns standing on
+// y=0, where column i spans [Xs[i], Xs[i+1]] and has height Heights[i].
+// Every histogram is a valid closed red-tile loop, which keeps shrinking from
+// producing inputs the solvers were never meant to handle.
+type histogram struct {
+	Xs      []int
+	Heights []int
+}
+
+// points walks the outline counter-clockwise from the bottom-left corner.
+func (h histogram) points() []Point {
+	pts := []Point{{h.Xs[0], 0}}
+	for i, height := range h.Heights {
+		pts = append(pts, Point{h.Xs[i], height}, Point{h.Xs[i+1], height})
+	}
+	pts = append(pts, Point{h.Xs[len(h.Xs)-1], 0})
+
+	// Drop the duplicate corner that appears between equal-height columns.
+	out := pts[:0:0]
+	for _, p := range pts {
+		if len(out) == 0 || out[len(out)-1] != p {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (h histogram) valid() bool {
+	if len(h.Xs) < 2 || len(h.Heights) != len(h.Xs)-1 {
+		return false
+	}
+	for i := 1; i < len(h.Xs); i++ {
+		if h.Xs[i] <= h.Xs[i-1] {
+			return false
+		}
+	}
+	for i, height := range h.Heights {
+		if height < 1 || (i > 0 && height == h.Heights[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h histogram) String() string {
+	var sb strings.Builder
+	for _, p := range h.points() {
+		fmt.Fprintf(&sb, "%d,%d\n", p.X, p.Y)
+	}
+	return sb.String()
+}
+
+func TestPart2MatchesBruteForce(t *testing.T) {
+	reference := difftest.WithFile(t, Part2BruteForce)
+	candidate := difftest.WithFile(t, Part2)
+
+	difftest.Check(t, difftest.Property[histogram]{
+		Name:       "part2",
+		Reference:  func(h histogram) (int, error) { return reference(h.String()) },
+		Candidate:  func(h histogram) (int, error) { return candidate(h.String()) },
+		Iterations: 100,
+		Generate: func(r *rand.Rand) histogram {
+			columns := 1 + r.Intn(5)
+			h := histogram{Xs: []int{r.Intn(3)}}
+			for i := 0; i < columns; i++ {
+				h.Xs = append(h.Xs, h.Xs[i]+1+r.Intn(4))
+				height := 1 + r.Intn(6)
+				for i > 0 && height == h.Heights[i-1] {
+					height = 1 + r.Intn(6)
+				}
+				h.Heights = append(h.Heights, height)
+			}
+			return h
+		},
+		Shrink: func(h histogram) []histogram {
+			var out []histogram
+			// Merge a column into its neighbour by dropping the shared x.
+			for i := 1; i < len(h.Xs)-1; i++ {
+				xs := append(append([]int{}, h.Xs[:i]...), h.Xs[i+1:]...)
+				heights := append(append([]int{}, h.Heights[:i]...), h.Heights[i+1:]...)
+				out = append(out, histogram{xs, heights})
+			}
+			// Narrow or lower a single column.
+			for i := range h.Heights {
+				for _, height := range difftest.ShrinkInt(h.Heights[i], 1) {
+					heights := append([]int{}, h.Heights...)
+					heights[i] = height
+					out = append(out, histogram{h.Xs, heights})
+				}
+				xs := append([]int{}, h.Xs...)
+				for j := i + 1; j < len(xs); j++ {
+					xs[j]--
+				}
+				out = append(out, histogram{xs, h.Heights})
+			}
+			valid := out[:0]
+			for _, c := range out {
+				if c.valid() {
+					valid = append(valid, c)
+				}
+			}
+			return valid
+		},
+		Encode: histogram.String,
+		Decode: func(s string) (histogram, error) {
+			lines, _ := difftest.Lines(s)
+			points, err := ParsePoints(lines)
+			if err != nil {
+				return histogram{}, err
+			}
+			return histogramFromPoints(points)
+		},
+	})
+}
+
+// histogramFromPoints recovers the column description from a saved outline.
+func histogramFromPoints(points []Point) (histogram, error) {
+	var h histogram
+	for i := 1; i+1 < len(points); i += 2 {
+		h.Xs = append(h.Xs, points[i].X)
+		h.Heights = append(h.Heights, points[i].Y)
+	}
+	if len(points) > 0 {
+		h.Xs = append(h.Xs, points[len(points)-1].X)
+	}
+	if !h.valid() {
+		return histogram{}, fmt.Errorf("points do not describe a histogram outline")
+	}
+	return h, nil
+}
@@ -29,11 +29,17 @@ func Part2(inputPath string) (int, error) {
 	}
 
 	// Step 1: Extract unique coordinates
+	// Each coordinate's successor is kept too, so the open gap between two
+	// neighbouring coordinates has a representative cell. Without it a
+	// one-column notch of outside tiles between two edges would be invisible
+	// in compressed space (found by the difftest harness).
 	xSet := make(map[int]bool)
 	ySet := make(map[int]bool)
 	for _, p := range redTiles {
 		xSet[p.X] = true
+		xSet[p.X+1] = true
 		ySet[p.Y] = true
+		ySet[p.Y+1] = true
 	}
 
 	// Convert to sorted slices
@@ -0,0 +1,290 @@
+// Package difftest cross-checks two implementations of the same puzzle
+// solution on randomly generated inputs.
+//
+// Several days keep a slow, obviously-correct reference next to an optimised
+// solver (day 9's Part2BruteForce, day 3's documented brute force, day 10's
+// interchangeable strategies). A Property pairs such implementations with an
+// input generator. Run feeds both the same random inputs; when they disagree
+// the input is shrunk to a minimal failing case and written to
+// testdata/difftest/<name>/ so that Replay turns it into a permanent
+// regression test on the next run.
+package difftest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var (
+	seedFlag  = flag.Int64("difftest.seed", 1, "seed for difftest input generation")
+	itersFlag = flag.Int("difftest.iters", 0, "override the number of generated inputs per property")
+)
+
+// Solver is a single implementation under comparison.
+type Solver[T any] func(T) (int, error)
+
+// Property describes a pair of implementations that must agree on every input.
+type Property[T any] struct {
+	// Name identifies the property; it also names the testdata directory.
+	Name string
+
+	// Reference is the trusted (usually brute-force) implementation.
+	Reference Solver[T]
+	// Candidate is the optimised implementation being checked.
+	Candidate Solver[T]
+
+	// Generate produces a random input.
+	Generate func(r *rand.Rand) T
+	// Shrink returns smaller variants of an input, most aggressive first.
+	// It may be nil, in which case failures are recorded unshrunk.
+	Shrink func(T) []T
+
+	// Encode and Decode convert an input to and from its testdata form.
+	// For most days this is simply the puzzle input text.
+	Encode func(T) string
+	Decode func(string) (T, error)
+
+	// Iterations is the number of random inputs to try (default 200).
+	Iterations int
+}
+
+// outcome is the observable result of running one solver.
+type outcome struct {
+	value int
+	err   error
+}
+
+func (o outcome) String() string {
+	if o.err != nil {
+		return fmt.Sprintf("error(%v)", o.err)
+	}
+	return fmt.Sprintf("%d", o.value)
+}
+
+// agrees treats two errors as agreement; only the values are compared otherwise.
+func (o outcome) agrees(other outcome) bool {
+	if (o.err != nil) != (other.err != nil) {
+		return false
+	}
+	return o.err != nil || o.value == other.value
+}
+
+func run[T any](s Solver[T], input T) (o outcome) {
+	defer func() {
+		if r := recover(); r != nil {
+			o = outcome{err: fmt.Errorf("panic: %v", r)}
+		}
+	}()
+	v, err := s(input)
+	return outcome{value: v, err: err}
+}
+
+// disagree runs both solvers and reports whether they differ.
+func (p Property[T]) disagree(input T) (ref, cand outcome, differ bool) {
+	ref = run(p.Reference, input)
+	cand = run(p.Candidate, input)
+	return ref, cand, !ref.agrees(cand)
+}
+
+// Run checks the property on generated inputs. The first disagreement is
+// shrunk, saved under testdata and reported as a test failure.
+func Run[T any](t *testing.T, p Property[T]) {
+	t.Helper()
+
+	iterations := p.Iterations
+	if iterations <= 0 {
+		iterations = 200
+	}
+	if *itersFlag > 0 {
+		iterations = *itersFlag
+	}
+	if testing.Short() {
+		iterations = min(iterations, 20)
+	}
+
+	r := rand.New(rand.NewSource(*seedFlag))
+	for i := 0; i < iterations; i++ {
+		input := p.Generate(r)
+		if _, _, differ := p.disagree(input); !differ {
+			continue
+		}
+
+		minimal := p.shrink(input)
+		ref, cand, _ := p.disagree(minimal)
+		encoded := p.Encode(minimal)
+
+		path, err := save(p.Name, encoded)
+		if err != nil {
+			t.Errorf("saving regression case: %v", err)
+		}
+		t.Fatalf("%s: implementations disagree after %d inputs (seed %d)\nreference: %v\ncandidate: %v\nminimal input (saved to %s):\n%s",
+			p.Name, i+1, *seedFlag, ref, cand, path, encoded)
+	}
+}
+
+// shrink greedily replaces the input with the first smaller variant that
+// still makes the implementations disagree, until no variant does.
+func (p Property[T]) shrink(input T) T {
+	if p.Shrink == nil {
+		return input
+	}
+	for {
+		progressed := false
+		for _, candidate := range p.Shrink(input) {
+			if _, _, differ := p.disagree(candidate); differ {
+				input = candidate
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			return input
+		}
+	}
+}
+
+// Dir returns the testdata directory holding regression cases for a property.
+func Dir(name string) string {
+	return filepath.Join("testdata", "difftest", name)
+}
+
+// save writes a regression case named after the hash of its contents, so the
+// same minimal input found twice is only stored once.
+func save(name, encoded string) (string, error) {
+	dir := Dir(name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", dir, err)
+	}
+	sum := sha256.Sum256([]byte(encoded))
+	path := filepath.Join(dir, hex.EncodeToString(sum[:8])+".txt")
+	if err := os.WriteFile(path, []byte(encoded), 0o644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// Replay runs every saved regression case for the property as a subtest.
+func Replay[T any](t *testing.T, p Property[T]) {
+	t.Helper()
+
+	entries, err := os.ReadDir(Dir(p.Name))
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		t.Fatalf("reading regression cases: %v", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".txt") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(Dir(p.Name), name))
+			if err != nil {
+				t.Fatalf("reading case: %v", err)
+			}
+			input, err := p.Decode(string(data))
+			if err != nil {
+				t.Fatalf("decoding case: %v", err)
+			}
+			if ref, cand, differ := p.disagree(input); differ {
+				t.Errorf("reference: %v, candidate: %v\ninput:\n%s", ref, cand, data)
+			}
+		})
+	}
+}
+
+// Check is the usual entry point: replay saved regressions, then search for new ones.
+func Check[T any](t *testing.T, p Property[T]) {
+	t.Helper()
+	t.Run("regressions", func(t *testing.T) { Replay(t, p) })
+	t.Run("random", func(t *testing.T) { Run(t, p) })
+}
+
+// ShrinkSlice returns variants of s with chunks removed (halves first, then
+// single elements) followed by variants with one element shrunk by elem.
+// elem may be nil when elements cannot be simplified further.
+func ShrinkSlice[E any](s []E, elem func(E) []E) [][]E {
+	var out [][]E
+	for size := len(s) / 2; size >= 1; size /= 2 {
+		for start := 0; start+size <= len(s); start += size {
+			out = append(out, remove(s, start, size))
+		}
+	}
+	if elem == nil {
+		return out
+	}
+	for i, e := range s {
+		for _, smaller := range elem(e) {
+			variant := make([]E, len(s))
+			copy(variant, s)
+			variant[i] = smaller
+			out = append(out, variant)
+		}
+	}
+	return out
+}
+
+func remove[E any](s []E, start, size int) []E {
+	out := make([]E, 0, len(s)-size)
+	out = append(out, s[:start]...)
+	return append(out, s[start+size:]...)
+}
+
+// ShrinkInt returns values between lo and n that move n towards lo.
+func ShrinkInt(n, lo int) []int {
+	if n <= lo {
+		return nil
+	}
+	out := []int{lo}
+	if mid := lo + (n-lo)/2; mid != lo {
+		out = append(out, mid)
+	}
+	if n-1 != lo && n-1 != lo+(n-lo)/2 {
+		out = append(out, n-1)
+	}
+	return out
+}
+
+// Lines and JoinLines are Encode/Decode helpers for inputs that are a list of lines.
+func Lines(s string) ([]string, error) {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out, nil
+}
+
+// JoinLines is the inverse of Lines.
+func JoinLines(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// WithFile adapts a solver that reads its input from a path (every day's
+// Part1/Part2) into one that takes the input text directly.
+func WithFile(t testing.TB, solve func(string) (int, error)) Solver[string] {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	return func(input string) (int, error) {
+		if err := os.WriteFile(path, []byte(input), 0o644); err != nil {
+			return 0, err
+		}
+		return solve(path)
+	}
+}
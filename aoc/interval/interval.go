@@ -0,0 +1,287 @@
+// Package interval provides sets of integers stored as closed intervals.
+//
+// Several puzzles describe their inputs as inclusive ranges ("3-5", "11-22").
+// A Set keeps such ranges normalised: sorted, disjoint and never touching, so
+// that [1,3] and [4,6] are held as the single interval [1,6]. Normalisation
+// makes the set's size the sum of its intervals' lengths and lets membership
+// be answered with a binary search instead of a scan over every input range.
+package interval
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Interval is the closed range of integers [Start, End]. An interval with End
+// before Start is empty.
+type Interval struct {
+	Start, End int
+}
+
+// Contains reports whether x lies in the interval.
+func (iv Interval) Contains(x int) bool {
+	return x >= iv.Start && x <= iv.End
+}
+
+// IsEmpty reports whether the interval holds no integers.
+func (iv Interval) IsEmpty() bool {
+	return iv.End < iv.Start
+}
+
+// Len returns how many integers the interval holds. It overflows for
+// intervals wider than math.MaxInt.
+func (iv Interval) Len() int {
+	if iv.IsEmpty() {
+		return 0
+	}
+	return iv.End - iv.Start + 1
+}
+
+// String formats the interval the way puzzle inputs write ranges (e.g. "3-5").
+func (iv Interval) String() string {
+	return fmt.Sprintf("%d-%d", iv.Start, iv.End)
+}
+
+// MarshalText encodes the interval in its input form, so it appears as "3-5"
+// in JSON.
+func (iv Interval) MarshalText() ([]byte, error) {
+	return []byte(iv.String()), nil
+}
+
+// touches reports whether b, starting at or after a's start, overlaps a or
+// begins right after it, so that the two merge into one interval.
+func touches(a, b Interval) bool {
+	return a.End == math.MaxInt || b.Start <= a.End+1
+}
+
+// Set is a set of integers held as sorted, disjoint, non-adjacent intervals.
+// The zero value is the empty set.
+//
+// Insert and Delete change the set they are called on; Union, Intersect,
+// Difference and Complement return new sets and leave their operands alone.
+// A Set is a small value over a shared list of intervals, so copies are cheap,
+// and Insert and Delete build a new list rather than editing the shared one:
+// changing one copy never changes another.
+type Set struct {
+	intervals []Interval
+}
+
+// NewSet returns the union of the given intervals. Empty intervals are ignored.
+//
+// Sorting by start and sweeping once merges everything in O(n log n): each
+// interval either extends the last merged one or starts a new one.
+func NewSet(intervals ...Interval) Set {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, iv := range intervals {
+		if !iv.IsEmpty() {
+			sorted = append(sorted, iv)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var s Set
+	for _, iv := range sorted {
+		s.appendMerged(iv)
+	}
+	return s
+}
+
+// appendMerged adds an interval that starts at or after every interval already
+// in the set, merging it with the last one when they touch.
+func (s *Set) appendMerged(iv Interval) {
+	if n := len(s.intervals); n > 0 && touches(s.intervals[n-1], iv) {
+		s.intervals[n-1].End = max(s.intervals[n-1].End, iv.End)
+		return
+	}
+	s.intervals = append(s.intervals, iv)
+}
+
+// Intervals returns a copy of the set's intervals in increasing order.
+func (s Set) Intervals() []Interval {
+	return append([]Interval(nil), s.intervals...)
+}
+
+// IsEmpty reports whether the set holds no integers.
+func (s Set) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Len returns how many integers the set holds.
+func (s Set) Len() int {
+	total := 0
+	for _, iv := range s.intervals {
+		total += iv.Len()
+	}
+	return total
+}
+
+// find returns the index of the first interval ending at or after x.
+func (s Set) find(x int) int {
+	return sort.Search(len(s.intervals), func(i int) bool { return s.intervals[i].End >= x })
+}
+
+// Contains reports whether x is in the set, by binary search in O(log n).
+func (s Set) Contains(x int) bool {
+	i := s.find(x)
+	return i < len(s.intervals) && s.intervals[i].Start <= x
+}
+
+// Insert adds every integer in iv to the set.
+//
+// The intervals iv overlaps or touches form one contiguous run, found by
+// binary search; the run is replaced by a single interval covering it and iv.
+func (s *Set) Insert(iv Interval) {
+	if iv.IsEmpty() {
+		return
+	}
+	// First interval that could touch iv: ends at or after iv.Start-1
+	lo := 0
+	if iv.Start > math.MinInt {
+		lo = s.find(iv.Start - 1)
+	}
+	// First interval entirely after iv, with a gap between
+	hi := lo
+	for hi < len(s.intervals) && touches(iv, s.intervals[hi]) {
+		hi++
+	}
+
+	merged := iv
+	if lo < hi {
+		merged.Start = min(merged.Start, s.intervals[lo].Start)
+		merged.End = max(merged.End, s.intervals[hi-1].End)
+	}
+	s.splice(lo, hi, merged)
+}
+
+// Delete removes every integer in iv from the set. An interval that straddles
+// iv keeps the parts on either side.
+func (s *Set) Delete(iv Interval) {
+	if iv.IsEmpty() {
+		return
+	}
+	lo := s.find(iv.Start)
+	hi := lo
+	for hi < len(s.intervals) && s.intervals[hi].Start <= iv.End {
+		hi++
+	}
+	if lo == hi {
+		return
+	}
+
+	var kept []Interval
+	if first := s.intervals[lo]; first.Start < iv.Start {
+		kept = append(kept, Interval{first.Start, iv.Start - 1})
+	}
+	if last := s.intervals[hi-1]; last.End > iv.End {
+		kept = append(kept, Interval{iv.End + 1, last.End})
+	}
+	s.splice(lo, hi, kept...)
+}
+
+// splice replaces intervals lo to hi-1 with the given ones, in a new list so
+// that copies of the set sharing the old one are unaffected.
+func (s *Set) splice(lo, hi int, with ...Interval) {
+	out := make([]Interval, 0, len(s.intervals)-(hi-lo)+len(with))
+	out = append(out, s.intervals[:lo]...)
+	out = append(out, with...)
+	s.intervals = append(out, s.intervals[hi:]...)
+}
+
+// Union returns the integers in either set.
+//
+// Both interval lists are already sorted, so a merge in O(n + m) replaces the
+// sort NewSet would need.
+func (s Set) Union(o Set) Set {
+	var out Set
+	i, j := 0, 0
+	for i < len(s.intervals) || j < len(o.intervals) {
+		if j == len(o.intervals) || (i < len(s.intervals) && s.intervals[i].Start <= o.intervals[j].Start) {
+			out.appendMerged(s.intervals[i])
+			i++
+		} else {
+			out.appendMerged(o.intervals[j])
+			j++
+		}
+	}
+	return out
+}
+
+// Intersect returns the integers in both sets, in O(n + m).
+//
+// Two pointers walk the lists; whichever interval ends first can meet nothing
+// further in the other list, so it is the one to advance.
+func (s Set) Intersect(o Set) Set {
+	var out Set
+	i, j := 0, 0
+	for i < len(s.intervals) && j < len(o.intervals) {
+		a, b := s.intervals[i], o.intervals[j]
+		if iv := (Interval{max(a.Start, b.Start), min(a.End, b.End)}); !iv.IsEmpty() {
+			out.intervals = append(out.intervals, iv)
+		}
+		if a.End < b.End {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// Complement returns the integers within bound that are not in the set.
+func (s Set) Complement(bound Interval) Set {
+	var out Set
+	if bound.IsEmpty() {
+		return out
+	}
+	next := bound.Start // smallest integer not yet accounted for
+	for _, iv := range s.intervals {
+		if iv.End < next {
+			continue
+		}
+		if iv.Start > bound.End {
+			break
+		}
+		if iv.Start > next {
+			out.intervals = append(out.intervals, Interval{next, iv.Start - 1})
+		}
+		if iv.End >= bound.End {
+			return out
+		}
+		next = iv.End + 1
+	}
+	out.intervals = append(out.intervals, Interval{next, bound.End})
+	return out
+}
+
+// Difference returns the integers in s that are not in o.
+func (s Set) Difference(o Set) Set {
+	if s.IsEmpty() {
+		return Set{}
+	}
+	bound := Interval{s.intervals[0].Start, s.intervals[len(s.intervals)-1].End}
+	return s.Intersect(o.Complement(bound))
+}
+
+// Equal reports whether the two sets hold the same integers.
+func (s Set) Equal(o Set) bool {
+	if len(s.intervals) != len(o.intervals) {
+		return false
+	}
+	for i := range s.intervals {
+		if s.intervals[i] != o.intervals[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String lists the intervals as puzzle inputs do, e.g. "3-5,10-20".
+func (s Set) String() string {
+	parts := make([]string, len(s.intervals))
+	for i, iv := range s.intervals {
+		parts[i] = iv.String()
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,151 @@
+package interval
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// universe bounds the random tests; sets are checked against a plain bitmap
+const lo, hi = -30, 30
+
+type bitmap [hi - lo + 1]bool
+
+func (b *bitmap) set(iv Interval, v bool) {
+	for x := max(iv.Start, lo); x <= min(iv.End, hi); x++ {
+		b[x-lo] = v
+	}
+}
+
+func randomInterval(r *rand.Rand) Interval {
+	start := lo + r.Intn(hi-lo-10)
+	return Interval{start, start + r.Intn(12) - 1} // occasionally empty
+}
+
+func randomSet(r *rand.Rand) (Set, bitmap) {
+	var ivs []Interval
+	var b bitmap
+	for i := r.Intn(6); i > 0; i-- {
+		iv := randomInterval(r)
+		ivs = append(ivs, iv)
+		b.set(iv, true)
+	}
+	return NewSet(ivs...), b
+}
+
+// check verifies membership, size and normalisation against the bitmap
+func check(t *testing.T, what string, s Set, want bitmap) {
+	t.Helper()
+	count := 0
+	for x := lo; x <= hi; x++ {
+		if s.Contains(x) != want[x-lo] {
+			t.Fatalf("%s = %v: Contains(%d) = %v", what, s, x, s.Contains(x))
+		}
+		if want[x-lo] {
+			count++
+		}
+	}
+	if s.Len() != count {
+		t.Fatalf("%s = %v: Len = %d, want %d", what, s, s.Len(), count)
+	}
+	ivs := s.Intervals()
+	for i, iv := range ivs {
+		if iv.IsEmpty() || (i > 0 && iv.Start <= ivs[i-1].End+1) {
+			t.Fatalf("%s = %v is not normalised", what, s)
+		}
+	}
+}
+
+func TestSetMatchesBitmap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 2000; trial++ {
+		a, am := randomSet(r)
+		b, bm := randomSet(r)
+		check(t, "NewSet", a, am)
+
+		var union, inter, diff, comp bitmap
+		bound := randomInterval(r)
+		for i := range union {
+			union[i] = am[i] || bm[i]
+			inter[i] = am[i] && bm[i]
+			diff[i] = am[i] && !bm[i]
+			comp[i] = !am[i] && bound.Contains(lo+i)
+		}
+		check(t, a.String()+" ∪ "+b.String(), a.Union(b), union)
+		check(t, a.String()+" ∩ "+b.String(), a.Intersect(b), inter)
+		check(t, a.String()+" − "+b.String(), a.Difference(b), diff)
+		check(t, a.String()+" complement in "+bound.String(), a.Complement(bound), comp)
+
+		iv := randomInterval(r)
+		if r.Intn(2) == 0 {
+			a.Insert(iv)
+			am.set(iv, true)
+			check(t, "after Insert("+iv.String()+")", a, am)
+		} else {
+			a.Delete(iv)
+			am.set(iv, false)
+			check(t, "after Delete("+iv.String()+")", a, am)
+		}
+	}
+}
+
+func TestOperandsUnchanged(t *testing.T) {
+	a := NewSet(Interval{1, 5}, Interval{10, 20})
+	b := NewSet(Interval{3, 12})
+	a.Union(b)
+	a.Intersect(b)
+	a.Difference(b)
+	a.Complement(Interval{0, 30})
+	if a.String() != "1-5,10-20" || b.String() != "3-12" {
+		t.Errorf("operands changed: %v, %v", a, b)
+	}
+}
+
+func TestCopiesIndependent(t *testing.T) {
+	s := NewSet(Interval{1, 2}, Interval{5, 6}, Interval{9, 10})
+	c := s
+	s.Insert(Interval{5, 10})
+	if c.String() != "1-2,5-6,9-10" {
+		t.Errorf("Insert on a copy changed the original: %v", c)
+	}
+	c = s
+	s.Delete(Interval{1, 1})
+	if c.String() != "1-2,5-10" {
+		t.Errorf("Delete on a copy changed the original: %v", c)
+	}
+}
+
+func TestAdjacentIntervalsMerge(t *testing.T) {
+	s := NewSet(Interval{4, 6}, Interval{1, 3}, Interval{8, 9})
+	if got := s.String(); got != "1-6,8-9" {
+		t.Errorf("NewSet = %s, want 1-6,8-9", got)
+	}
+	s.Insert(Interval{7, 7})
+	if got := s.String(); got != "1-9" {
+		t.Errorf("after Insert(7-7) = %s, want 1-9", got)
+	}
+	s.Delete(Interval{5, 5})
+	if got := s.String(); got != "1-4,6-9" {
+		t.Errorf("after Delete(5-5) = %s, want 1-4,6-9", got)
+	}
+}
+
+func TestExtremes(t *testing.T) {
+	full := Interval{math.MinInt, math.MaxInt}
+	s := NewSet(Interval{math.MinInt, -1}, Interval{0, math.MaxInt})
+	if !s.Equal(NewSet(full)) {
+		t.Errorf("halves of the int range should merge, got %v", s)
+	}
+	if c := s.Complement(full); !c.IsEmpty() {
+		t.Errorf("complement of everything = %v", c)
+	}
+
+	s.Delete(Interval{0, 0})
+	if !s.Contains(math.MinInt) || !s.Contains(math.MaxInt) || s.Contains(0) {
+		t.Errorf("after deleting 0: %v", s)
+	}
+	s.Insert(Interval{math.MinInt, 0})
+	if !s.Equal(NewSet(full)) {
+		t.Errorf("reinserting should restore everything, got %v", s)
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"math/big"
+	"math/rand"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
@@ -22,6 +28,9 @@ import (
 	day11 "adv2025/aoc/day11"
 	day12 "adv2025/aoc/day12"
 	day25 "adv2025/aoc/day25"
+	"adv2025/internal/cache"
+	"adv2025/internal/scaffold"
+	"adv2025/internal/watch"
 )
 
 type solver struct {
@@ -52,11 +61,67 @@ func init() {
 	register(11, day11.Parts...)
 	register(12, day12.Parts...)
 	register(25, day25.Parts...)
+
+	// Days that can answer every part from a single parse of the input.
+	combine(1, day1.Solve)
+}
+
+// combine makes the registered parts of a day share one call to solve, which
+// returns all answers in part order. The first part to run does the work and
+// the others reuse its answers for the same input.
+func combine(day int, solve func(string) ([]int, error)) {
+	type answers struct {
+		values []int
+		err    error
+	}
+	memo := map[string]answers{}
+
+	for i := range solvers {
+		if solvers[i].day != day {
+			continue
+		}
+		part := solvers[i].part
+		solvers[i].solve = func(inputPath string) (int, error) {
+			a, ok := memo[inputPath]
+			if !ok {
+				a.values, a.err = solve(inputPath)
+				memo[inputPath] = a
+			}
+			if a.err != nil {
+				return 0, a.err
+			}
+			if part > len(a.values) {
+				return 0, fmt.Errorf("combined solver returned %d answers, no part %d", len(a.values), part)
+			}
+			return a.values[part-1], nil
+		}
+	}
+}
+
+// commands are subcommands selected by the first argument (e.g. "aoc new -day 13").
+// Without a subcommand the runner solves puzzles as before.
+var commands = map[string]func(args []string) error{
+	"new":   newDay,
+	"cache": cacheCommand,
+	"watch": watchCommand,
+	"day2":  day2Command,
+	"day5":  day5Command,
+	"day7":  day7Command,
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
 	day := flag.Int("day", 0, "Day to run (0 for all)")
 	part := flag.Int("part", 0, "Part to run (0 for all parts of the day)")
+	noCache := flag.Bool("no-cache", false, "Re-solve everything, ignoring cached results")
 	flag.Parse()
 
 	toRun := filterSolvers(*day, *part)
@@ -64,14 +129,28 @@ func main() {
 		log.Fatalf("No solutions found for day %d part %d", *day, *part)
 	}
 
+	var results *cache.Cache
+	if !*noCache {
+		var err error
+		if results, err = cache.Open(cache.DefaultPath); err != nil {
+			log.Printf("⚠️  Result cache disabled: %v", err)
+		}
+	}
+
 	printHeader()
 	totalStart := time.Now()
 
 	for _, s := range toRun {
-		runSolver(s)
+		runSolver(s, results)
 	}
 
 	fmt.Printf("\n⏱️  Total time: %v\n", time.Since(totalStart))
+
+	if results != nil {
+		if err := results.Save(); err != nil {
+			log.Printf("⚠️  Saving result cache: %v", err)
+		}
+	}
 }
 
 func filterSolvers(day, part int) []solver {
@@ -88,7 +167,9 @@ func filterSolvers(day, part int) []solver {
 	return filtered
 }
 
-func runSolver(s solver) {
+// runSolver solves one part, answering from results when the input and the
+// day's source are unchanged. results may be nil to always solve.
+func runSolver(s solver, results *cache.Cache) {
 	inputPath := filepath.Join("inputs", fmt.Sprintf("day%d_input.txt", s.day))
 
 	if _, err := os.Stat(inputPath); err != nil {
@@ -97,18 +178,312 @@ func runSolver(s solver) {
 	}
 
 	start := time.Now()
+
+	// A solver whose key cannot be computed (e.g. the binary runs away from
+	// the source tree) is simply solved without the cache.
+	key, keyErr := cacheKey(s, inputPath)
+	cacheable := results != nil && keyErr == nil
+	if cacheable {
+		if result, ok := results.Get(key); ok {
+			fmt.Printf("✅ Day %d Part %d: %d (%v, cached)\n", s.day, s.part, result, time.Since(start))
+			return
+		}
+	}
+
 	result, err := s.solve(inputPath)
 	elapsed := time.Since(start)
 
 	if err != nil {
 		fmt.Printf("❌ Day %d Part %d: %v\n", s.day, s.part, err)
 	} else {
+		if cacheable {
+			results.Put(key, result)
+		}
 		fmt.Printf("✅ Day %d Part %d: %d (%v)\n", s.day, s.part, result, elapsed)
 	}
 }
 
+// sourceHashes memoises cache.SourceHash per day; both parts share a package.
+var sourceHashes = map[int]string{}
+
+func cacheKey(s solver, inputPath string) (cache.Key, error) {
+	inputHash, err := cache.HashFile(inputPath)
+	if err != nil {
+		return cache.Key{}, err
+	}
+
+	sourceHash, ok := sourceHashes[s.day]
+	if !ok {
+		sourceHash, err = cache.SourceHash(".", filepath.Join("aoc", fmt.Sprintf("day%d", s.day)))
+		if err != nil {
+			return cache.Key{}, err
+		}
+		sourceHashes[s.day] = sourceHash
+	}
+
+	return cache.Key{Day: s.day, Part: s.part, Input: inputHash, Source: sourceHash}, nil
+}
+
 func printHeader() {
 	fmt.Println("🎄 Advent of Code 2025 Runner")
 	fmt.Println(strings.Repeat("=", 50))
 	fmt.Println()
 }
+
+// newDay generates a new day package from templates and registers it here.
+func newDay(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	day := fs.Int("day", 0, "Day to create (1-25)")
+	fs.Parse(args)
+
+	files, err := scaffold.New(".", *day)
+	if err != nil {
+		return fmt.Errorf("creating day %d: %w", *day, err)
+	}
+
+	for _, f := range files {
+		fmt.Printf("📝 %s\n", f)
+	}
+	fmt.Printf("\nSave your puzzle input as %s and run:\n", filepath.Join("inputs", fmt.Sprintf("day%d_input.txt", *day)))
+	fmt.Printf("  go run cmd/main.go -day %d\n", *day)
+	return nil
+}
+
+// cacheCommand manages the result cache ("aoc cache clear").
+func cacheCommand(args []string) error {
+	if len(args) != 1 || args[0] != "clear" {
+		return fmt.Errorf("usage: cache clear")
+	}
+	if err := cache.Clear(cache.DefaultPath); err != nil {
+		return err
+	}
+	fmt.Println("🧹 Result cache cleared")
+	return nil
+}
+
+// watchCommand polls a day's sources, tests and inputs, the runner and the
+// packages the day imports and, on every change, rebuilds the runner, solves
+// the day and runs its example tests ("aoc watch -day N").
+func watchCommand(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	day := fs.Int("day", 0, "Day to watch")
+	interval := fs.Duration("interval", 500*time.Millisecond, "How often to poll for changes")
+	fs.Parse(args)
+
+	pkgDir := filepath.Join("aoc", fmt.Sprintf("day%d", *day))
+	if _, err := os.Stat(pkgDir); err != nil {
+		return fmt.Errorf("watching day %d: %w", *day, err)
+	}
+
+	binDir, err := os.MkdirTemp("", "aoc-watch")
+	if err != nil {
+		return fmt.Errorf("creating build directory: %w", err)
+	}
+	defer os.RemoveAll(binDir)
+	bin := filepath.Join(binDir, "aoc")
+
+	steps := []watch.Step{
+		{Name: "build", Args: []string{"go", "build", "-o", bin, "./cmd"}},
+		{Name: "solve", Args: []string{bin, "-day", fmt.Sprint(*day), "-no-cache"}, Summarize: watch.SolverLines},
+		// Only the example tests: the rest include slow random properties and
+		// tests that need the real input
+		{Name: "test", Args: []string{"go", "test", "-count=1", "-v", "-run", "Example", "./" + filepath.ToSlash(pkgDir)}, Summarize: watch.TestCounts},
+	}
+	scan := func() (watch.Snapshot, error) {
+		// The day package (tests and test data included), the runner, and
+		// the in-module packages the day imports, listed afresh each time so
+		// a new import is picked up
+		sources, err := cache.SourceFiles(".", pkgDir)
+		if err != nil {
+			return nil, err
+		}
+		globs := append(sources, filepath.Join("cmd", "*.go"), filepath.Join("inputs", fmt.Sprintf("day%d_*", *day)))
+		return watch.Scan([]string{pkgDir}, globs)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cycle := func(reason string) {
+		fmt.Printf("\n🔄 %s — %s\n", time.Now().Format("15:04:05"), reason)
+		watch.PrintSummary(os.Stdout, watch.RunSteps(ctx, steps))
+	}
+
+	fmt.Printf("👀 Watching day %d (polling every %v, Ctrl-C to stop)\n", *day, *interval)
+	cycle("initial run")
+	return watch.Poll(ctx, *interval, scan, func(changed []string) {
+		cycle(strings.Join(changed, ", "))
+	})
+}
+
+// day2Command evaluates variant invalid-ID rules against the same day 2 ranges
+// ("aoc day2 -rules 'exactly=2; at-least=3 base=16; palindrome'"), optionally as
+// a per-range report.
+func day2Command(args []string) error {
+	fs := flag.NewFlagSet("day2", flag.ExitOnError)
+	input := fs.String("input", filepath.Join("inputs", "day2_input.txt"), "Puzzle input")
+	rules := fs.String("rules", "exactly=2; at-least=2", "Semicolon-separated rules (see day2.ParseRule)")
+	report := fs.String("report", "", "Per-range report format: table or json")
+	limit := fs.Int("limit", 10, "Invalid IDs listed per range in a report (-1 for all)")
+	fs.Parse(args)
+
+	var validators []day2.Validator
+	for _, spec := range strings.Split(*rules, ";") {
+		v, err := day2.ParseRule(spec)
+		if err != nil {
+			return err
+		}
+		validators = append(validators, v)
+	}
+
+	parser, err := day2.FromFile(*input)
+	if err != nil {
+		return fmt.Errorf("loading input: %w", err)
+	}
+	ranges, err := parser.ParseAll()
+	if err != nil {
+		return fmt.Errorf("parsing ranges: %w", err)
+	}
+
+	switch *report {
+	case "":
+		start := time.Now()
+		results := day2.Evaluate(ranges, validators)
+		fmt.Printf("📋 %d rules over %d ranges (%v)\n", len(results), len(ranges), time.Since(start))
+		for _, r := range results {
+			fmt.Printf("  %-24v %8d IDs  sum %d\n", r.Rule, r.Count, r.Sum)
+		}
+	case "table":
+		for i, v := range validators {
+			if i > 0 {
+				fmt.Println()
+			}
+			if err := day2.NewReport(ranges, v, *limit).WriteTable(os.Stdout); err != nil {
+				return err
+			}
+		}
+	case "json":
+		reports := make([]day2.Report, len(validators))
+		for i, v := range validators {
+			reports[i] = day2.NewReport(ranges, v, *limit)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	default:
+		return fmt.Errorf("unknown report format %q (want table or json)", *report)
+	}
+	return nil
+}
+
+// day5Command classifies day 5 IDs while they are read ("aoc day5 -input - <
+// huge.txt"), so the ID section can be far larger than memory. Output is written
+// through a buffer flushed every -flush IDs; 1 writes each result immediately.
+func day5Command(args []string) error {
+	fs := flag.NewFlagSet("day5", flag.ExitOnError)
+	input := fs.String("input", filepath.Join("inputs", "day5_input.txt"), "Puzzle input, or - for stdin")
+	mode := fs.String("mode", "totals", "Output: ids (fresh/spoiled per ID) or totals (running counts)")
+	every := fs.Int("every", 1_000_000, "IDs between running totals")
+	flush := fs.Int("flush", 1, "IDs between output flushes")
+	fs.Parse(args)
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	var report func(id int, fresh bool, totals day5.Totals)
+	switch *mode {
+	case "ids":
+		report = func(id int, fresh bool, _ day5.Totals) {
+			state := "spoiled"
+			if fresh {
+				state = "fresh"
+			}
+			fmt.Fprintf(out, "%d %s\n", id, state)
+		}
+	case "totals":
+		report = func(_ int, _ bool, totals day5.Totals) {
+			if *every > 0 && totals.Seen()%*every == 0 {
+				fmt.Fprintf(out, "%d IDs: %d fresh, %d spoiled\n", totals.Seen(), totals.Fresh, totals.Spoiled)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown mode %q (want ids or totals)", *mode)
+	}
+
+	visit := func(id int, fresh bool, totals day5.Totals) error {
+		report(id, fresh, totals)
+		if *flush > 0 && totals.Seen()%*flush == 0 {
+			return out.Flush()
+		}
+		return nil
+	}
+
+	var totals day5.Totals
+	var err error
+	if *input == "-" {
+		totals, err = day5.NewParser(os.Stdin).Stream(visit)
+	} else {
+		totals, err = day5.StreamFile(*input, visit)
+	}
+	if err != nil {
+		return fmt.Errorf("streaming input: %w", err)
+	}
+
+	if *mode == "totals" {
+		fmt.Fprintf(out, "Total: %d IDs, %d fresh, %d spoiled\n", totals.Seen(), totals.Fresh, totals.Spoiled)
+	}
+	return out.Flush()
+}
+
+// day7Command shows day 7 timelines: by index in left/right order ("aoc day7
+// -k 0,39") or sampled uniformly ("aoc day7 -sample 3 -seed 1"), each drawn
+// over the grid. Indices may be far larger than an int.
+func day7Command(args []string) error {
+	fs := flag.NewFlagSet("day7", flag.ExitOnError)
+	input := fs.String("input", filepath.Join("inputs", "day7_input.txt"), "Puzzle input")
+	sides := fs.String("sides", "exit", "Beams leaving a row's ends: exit, wrap, absorb or reflect")
+	indices := fs.String("k", "", "Comma-separated timeline indices, counting from 0")
+	sample := fs.Int("sample", 0, "Timelines to sample uniformly at random")
+	seed := fs.Int64("seed", 1, "Random seed for -sample")
+	fs.Parse(args)
+
+	lines, err := day7.FromFile(*input)
+	if err != nil {
+		return fmt.Errorf("loading input: %w", err)
+	}
+	m, err := day7.NewManifold(lines)
+	if err != nil {
+		return err
+	}
+	if m.Sides, err = day7.ParseSide(*sides); err != nil {
+		return err
+	}
+	index, err := m.Timelines()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v timelines\n", index.Count())
+
+	show := func(label string, t *day7.Timeline) {
+		fmt.Printf("\n%s: source %d, choices %s\n", label, t.Source, t.Choices)
+		fmt.Println(strings.Join(m.Render(t), "\n"))
+	}
+	if *indices != "" {
+		for _, text := range strings.Split(*indices, ",") {
+			k, ok := new(big.Int).SetString(strings.TrimSpace(text), 10)
+			if !ok {
+				return fmt.Errorf("invalid timeline index %q", text)
+			}
+			t, err := index.At(k)
+			if err != nil {
+				return err
+			}
+			show(fmt.Sprintf("Timeline %v", k), t)
+		}
+	}
+	r := rand.New(rand.NewSource(*seed))
+	for i := 1; i <= *sample; i++ {
+		show(fmt.Sprintf("Sample %d", i), index.Sample(r))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	day1 "adv2025/aoc/day1"
+)
+
+func main() {
+	input := flag.String("input", "inputs/day1_input.txt", "Puzzle input")
+	format := flag.String("format", "csv", "Output: csv, json, animate or diff")
+	delay := flag.Duration("delay", 200*time.Millisecond, "Delay between animation frames")
+	size := flag.Int("size", 100, "Number of positions on the dial")
+	start := flag.Int("start", 50, "Starting position")
+	target := flag.Int("target", 0, "Target position")
+	flag.Parse()
+
+	// Both zero-crossing implementations run side by side so that "diff" can
+	// compare them; the other formats simply show them as two columns.
+	var trace day1.Trace
+	dial := day1.NewMultiDial([]day1.NamedCounter{
+		{Name: "end", Counter: day1.EndPositionCounter{}},
+		{Name: "crossings", Counter: day1.ZeroCrossingCounter{}},
+		{Name: "stepped", Counter: day1.StepCounter{}},
+	}, day1.WithSize(*size), day1.WithStart(*start), day1.WithTarget(*target), day1.WithTrace(&trace))
+
+	err := day1.ProcessFile(*input, func(r day1.Rotation) error {
+		dial.Rotate(r)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "csv":
+		err = trace.WriteCSV(os.Stdout)
+	case "json":
+		err = trace.WriteJSON(os.Stdout)
+	case "animate":
+		err = trace.Animate(os.Stdout, *delay)
+	case "diff":
+		var diffs []day1.StepDiff
+		diffs, err = day1.Diff(&trace, "crossings", &trace, "stepped")
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+		if err == nil {
+			fmt.Printf("%d of %d rotations differ\n", len(diffs), len(trace.Steps))
+		}
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
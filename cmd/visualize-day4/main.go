@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/gif"
+	"os"
+	"time"
+
+	day4 "adv2025/aoc/day4"
+)
+
+func main() {
+	input := flag.String("input", "inputs/day4_input.txt", "Puzzle input")
+	format := flag.String("format", "gif", "Output: gif, ascii or animate")
+	output := flag.String("output", "day4_removal.gif", "GIF file to write")
+	scale := flag.Int("scale", 6, "Pixels per grid cell in the GIF")
+	delay := flag.Duration("delay", 150*time.Millisecond, "Delay between frames")
+	asap := flag.Bool("asap", false, "Remove rolls as soon as they are accessible instead of in waves")
+	colour := flag.Bool("color", true, "Colour ASCII frames with ANSI escapes")
+	flag.Parse()
+
+	grid, err := day4.FromFile(*input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		os.Exit(1)
+	}
+
+	semantics := day4.WaveSynchronous
+	if *asap {
+		semantics = day4.ASAP
+	}
+	removal := day4.RemoveAll(grid, semantics)
+
+	switch *format {
+	case "gif":
+		fmt.Printf("Animating %d waves removing %d rolls...\n", len(removal.Waves), removal.Total)
+
+		outFile, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer outFile.Close()
+
+		// image/gif counts delays in hundredths of a second
+		anim := removal.GIF(*scale, int(*delay/(10*time.Millisecond)))
+		if err := gif.EncodeAll(outFile, anim); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding GIF: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved animation to %s\n", *output)
+	case "ascii":
+		err = removal.WriteFrames(os.Stdout, *colour)
+	case "animate":
+		err = removal.Animate(os.Stdout, *delay, *colour)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
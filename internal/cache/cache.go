@@ -0,0 +1,197 @@
+// Package cache stores puzzle answers so unchanged solvers are not re-run.
+//
+// An answer is keyed by day, part, the SHA-256 of the input file and a hash
+// of the solver's source: every non-test .go file of the day package plus the
+// in-module packages it imports. Editing the solver or the input therefore
+// invalidates the entry automatically; nothing has to be cleared by hand.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultPath is where the runner keeps its cache, relative to the repository root.
+const DefaultPath = ".aoc-cache/results.json"
+
+// Key identifies one cached answer.
+type Key struct {
+	Day    int
+	Part   int
+	Input  string // SHA-256 of the input file
+	Source string // hash of the solver's source files
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("day%d/part%d/%s/%s", k.Day, k.Part, k.Input, k.Source)
+}
+
+// Cache is a JSON file of answers loaded into memory.
+type Cache struct {
+	path    string
+	entries map[string]int
+	dirty   bool
+}
+
+// Open loads the cache at path. A missing file is an empty cache.
+func Open(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]int)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("decoding cache %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Get returns the cached answer for k, if any.
+func (c *Cache) Get(k Key) (int, bool) {
+	v, ok := c.entries[k.String()]
+	return v, ok
+}
+
+// Put records an answer. It is written to disk by Save.
+func (c *Cache) Put(k Key, answer int) {
+	c.entries[k.String()] = answer
+	c.dirty = true
+}
+
+// Save writes the cache back to disk if anything changed.
+func (c *Cache) Save() error {
+	if !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// Clear removes the cache file. Clearing a cache that does not exist is not an error.
+func Clear(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("clearing cache: %w", err)
+	}
+	return nil
+}
+
+// HashFile returns the hex SHA-256 of a file's contents.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+var moduleRe = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// SourceFiles lists the non-test Go files of the package in dir (relative to
+// the module root) together with those of every in-module package it
+// imports, directly or indirectly, in sorted order.
+func SourceFiles(root, dir string) ([]string, error) {
+	gomod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("reading go.mod: %w", err)
+	}
+	m := moduleRe.FindSubmatch(gomod)
+	if m == nil {
+		return nil, fmt.Errorf("go.mod has no module directive")
+	}
+	module := string(m[1])
+
+	// Collect the package closure first so the result does not depend on
+	// the order imports were discovered in.
+	seen := make(map[string]bool)
+	var files []string
+	var visit func(dir string) error
+	visit = func(dir string) error {
+		if seen[dir] {
+			return nil
+		}
+		seen[dir] = true
+
+		matches, err := filepath.Glob(filepath.Join(root, dir, "*.go"))
+		if err != nil {
+			return err
+		}
+		for _, path := range matches {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			files = append(files, path)
+
+			f, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+			if err != nil {
+				return fmt.Errorf("reading imports of %s: %w", path, err)
+			}
+			for _, imp := range f.Imports {
+				importPath := strings.Trim(imp.Path.Value, `"`)
+				if rest, ok := strings.CutPrefix(importPath, module+"/"); ok {
+					if err := visit(filepath.FromSlash(rest)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+	if err := visit(dir); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go source files in %s", dir)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// SourceHash hashes the files SourceFiles lists for the package in dir.
+func SourceHash(root, dir string) (string, error) {
+	files, err := SourceFiles(root, dir)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, path := range files {
+		rel, _ := filepath.Rel(root, path)
+		fileHash, err := HashFile(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %s\n", filepath.ToSlash(rel), fileHash)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
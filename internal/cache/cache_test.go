@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "results.json")
+	key := Key{Day: 1, Part: 2, Input: "abc", Source: "def"}
+
+	c, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if _, ok := c.Get(key); ok {
+		t.Fatal("empty cache returned a hit")
+	}
+	c.Put(key, 42)
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got, ok := reopened.Get(key); !ok || got != 42 {
+		t.Errorf("Get = %d, %v; want 42, true", got, ok)
+	}
+	if _, ok := reopened.Get(Key{Day: 1, Part: 2, Input: "abc", Source: "changed"}); ok {
+		t.Error("changed source hash still hit the cache")
+	}
+
+	if err := Clear(path); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if err := Clear(path); err != nil {
+		t.Fatalf("Clear of missing cache: %v", err)
+	}
+}
+
+func TestSourceHashFollowsModuleImports(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/m\n")
+	writeFile(t, filepath.Join(root, "aoc/day1/day1.go"), "package day1\n\nimport _ \"example.com/m/aoc/shared\"\n")
+	writeFile(t, filepath.Join(root, "aoc/day1/day1_test.go"), "package day1\n")
+	writeFile(t, filepath.Join(root, "aoc/shared/shared.go"), "package shared\n")
+
+	hash := func() string {
+		t.Helper()
+		h, err := SourceHash(root, "aoc/day1")
+		if err != nil {
+			t.Fatalf("SourceHash: %v", err)
+		}
+		return h
+	}
+
+	before := hash()
+	writeFile(t, filepath.Join(root, "aoc/day1/day1_test.go"), "package day1\n\n// tests do not affect answers\n")
+	if hash() != before {
+		t.Error("editing a test file changed the source hash")
+	}
+	writeFile(t, filepath.Join(root, "aoc/shared/shared.go"), "package shared\n\nconst X = 1\n")
+	if hash() == before {
+		t.Error("editing an imported package did not change the source hash")
+	}
+}
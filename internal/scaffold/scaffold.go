@@ -0,0 +1,195 @@
+// Package scaffold generates the skeleton of a new day package and registers
+// it with the runner in cmd/main.go.
+//
+// Every day follows the same layout (dayN.go with the Parts slice, parser.go,
+// part1.go, part2.go). The templates in templates/ encode that layout so a new
+// day starts from the shared input conventions instead of a hand-edited copy.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// ErrExists is returned when the day package already exists or is already
+// registered with the runner. Scaffolding never overwrites existing work.
+var ErrExists = errors.New("day already exists")
+
+// RunnerPath is the runner file, relative to the repository root, that New
+// registers the generated package in.
+const RunnerPath = "cmd/main.go"
+
+// file is one generated source file.
+type file struct {
+	name     string
+	template string
+	data     any
+}
+
+// New creates aoc/day<day> under root and registers it in the runner.
+// It returns the paths of all files it created or modified.
+func New(root string, day int) ([]string, error) {
+	if day < 1 || day > 25 {
+		return nil, fmt.Errorf("invalid day %d: must be between 1 and 25", day)
+	}
+
+	module, err := modulePath(root)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(root, "aoc", fmt.Sprintf("day%d", day))
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("%s: %w", dir, ErrExists)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("checking %s: %w", dir, err)
+	}
+
+	runnerPath := filepath.Join(root, RunnerPath)
+	runner, err := os.ReadFile(runnerPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading runner: %w", err)
+	}
+	registered, err := Register(runner, module, day)
+	if err != nil {
+		return nil, err
+	}
+
+	// Render everything before touching the disk so a template error
+	// cannot leave a half-written package behind.
+	files := []file{
+		{fmt.Sprintf("day%d.go", day), "day.go.tmpl", map[string]int{"Day": day}},
+		{"parser.go", "parser.go.tmpl", map[string]int{"Day": day}},
+		{"part1.go", "part.go.tmpl", map[string]int{"Day": day, "Part": 1}},
+		{"part2.go", "part.go.tmpl", map[string]int{"Day": day, "Part": 2}},
+		{"example_test.go", "example_test.go.tmpl", map[string]int{"Day": day}},
+	}
+	rendered := make(map[string][]byte, len(files))
+	for _, f := range files {
+		var buf bytes.Buffer
+		if err := templates.ExecuteTemplate(&buf, f.template, f.data); err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", f.name, err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("formatting %s: %w", f.name, err)
+		}
+		rendered[f.name] = src
+	}
+
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	var written []string
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, rendered[f.name], 0o644); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("writing %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+
+	if err := os.WriteFile(runnerPath, registered, 0o644); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("updating runner: %w", err)
+	}
+	return append(written, runnerPath), nil
+}
+
+var moduleRe = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// modulePath reads the module path from root/go.mod.
+func modulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", fmt.Errorf("reading go.mod: %w", err)
+	}
+	m := moduleRe.FindSubmatch(data)
+	if m == nil {
+		return "", fmt.Errorf("go.mod has no module directive")
+	}
+	return string(m[1]), nil
+}
+
+var (
+	importRe   = regexp.MustCompile(`^\s*day(\d+) "[^"]+/aoc/day\d+"\s*$`)
+	registerRe = regexp.MustCompile(`^\s*register\((\d+), day\d+\.Parts\.\.\.\)\s*$`)
+)
+
+// Register returns the runner source with an import and a register call for
+// the given day added next to the existing ones, keeping them in day order.
+func Register(runner []byte, module string, day int) ([]byte, error) {
+	lines := strings.Split(string(runner), "\n")
+
+	for _, line := range lines {
+		if m := registerRe.FindStringSubmatch(line); m != nil && m[1] == strconv.Itoa(day) {
+			return nil, fmt.Errorf("day %d is already registered in the runner: %w", day, ErrExists)
+		}
+	}
+
+	lines, err := insertSorted(lines, importRe, day,
+		fmt.Sprintf("\tday%d %q", day, fmt.Sprintf("%s/aoc/day%d", module, day)))
+	if err != nil {
+		return nil, fmt.Errorf("adding import: %w", err)
+	}
+	lines, err = insertSorted(lines, registerRe, day,
+		fmt.Sprintf("\tregister(%d, day%d.Parts...)", day, day))
+	if err != nil {
+		return nil, fmt.Errorf("adding registration: %w", err)
+	}
+
+	// The runner is not passed through gofmt: it would sort the imports
+	// lexically (day1, day10, day11, ...) and undo the day ordering.
+	src := []byte(strings.Join(lines, "\n"))
+	if _, err := parser.ParseFile(token.NewFileSet(), RunnerPath, src, parser.ImportsOnly); err != nil {
+		return nil, fmt.Errorf("updated runner does not parse: %w", err)
+	}
+	return src, nil
+}
+
+// insertSorted inserts line among the existing lines matching re (whose first
+// submatch is a day number), after the last one with a smaller day.
+func insertSorted(lines []string, re *regexp.Regexp, day int, line string) ([]string, error) {
+	first, at := -1, -1
+	for i, l := range lines {
+		m := re.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if n, _ := strconv.Atoi(m[1]); n < day {
+			at = i + 1
+		}
+	}
+	if first < 0 {
+		return nil, fmt.Errorf("no existing day entries found in %s", RunnerPath)
+	}
+	if at < 0 {
+		at = first
+	}
+
+	out := make([]string, 0, len(lines)+1)
+	out = append(out, lines[:at]...)
+	out = append(out, line)
+	return append(out, lines[at:]...), nil
+}
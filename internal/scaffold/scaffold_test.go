@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const runner = `package main
+
+import (
+	"fmt"
+
+	day1 "example.com/aoc/day1"
+	day2 "example.com/aoc/day2"
+	day10 "example.com/aoc/day10"
+)
+
+func init() {
+	register(1, day1.Parts...)
+	register(2, day2.Parts...)
+	register(10, day10.Parts...)
+}
+`
+
+func TestRegisterKeepsDayOrder(t *testing.T) {
+	got, err := Register([]byte(runner), "example.com", 3)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	src := string(got)
+	for _, before := range [][2]string{
+		{`day2 "example.com/aoc/day2"`, `day3 "example.com/aoc/day3"`},
+		{`day3 "example.com/aoc/day3"`, `day10 "example.com/aoc/day10"`},
+		{"register(2, day2.Parts...)", "register(3, day3.Parts...)"},
+		{"register(3, day3.Parts...)", "register(10, day10.Parts...)"},
+	} {
+		i, j := strings.Index(src, before[0]), strings.Index(src, before[1])
+		if i < 0 || j < 0 || i > j {
+			t.Errorf("expected %q before %q in:\n%s", before[0], before[1], src)
+		}
+	}
+}
+
+func TestRegisterRejectsDuplicates(t *testing.T) {
+	if _, err := Register([]byte(runner), "example.com", 10); !errors.Is(err, ErrExists) {
+		t.Errorf("Register(10) error = %v, want ErrExists", err)
+	}
+}
+
+func TestNewRefusesToOverwrite(t *testing.T) {
+	root := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com\n")
+	write(RunnerPath, runner)
+	write("aoc/day2/day2.go", "package day2\n")
+
+	if _, err := New(root, 2); !errors.Is(err, ErrExists) {
+		t.Fatalf("New(2) error = %v, want ErrExists", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "aoc/day2/day2.go")); string(data) != "package day2\n" {
+		t.Errorf("existing day was modified: %q", data)
+	}
+
+	files, err := New(root, 5)
+	if err != nil {
+		t.Fatalf("New(5): %v", err)
+	}
+	for _, name := range []string{"day5.go", "parser.go", "part1.go", "part2.go", "example_test.go"} {
+		if _, err := os.Stat(filepath.Join(root, "aoc", "day5", name)); err != nil {
+			t.Errorf("missing generated file %s: %v", name, err)
+		}
+	}
+	if len(files) != 6 {
+		t.Errorf("New returned %d files, want 6 (5 generated + runner)", len(files))
+	}
+
+	updated, _ := os.ReadFile(filepath.Join(root, RunnerPath))
+	if !strings.Contains(string(updated), "register(5, day5.Parts...)") {
+		t.Errorf("runner not updated:\n%s", updated)
+	}
+}
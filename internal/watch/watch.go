@@ -0,0 +1,225 @@
+// Package watch re-runs a day's solver and tests whenever its files change.
+//
+// Changes are detected by polling file sizes and modification times, so it
+// works everywhere the standard library does and needs no watcher dependency.
+package watch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileState is what polling compares between scans.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Snapshot maps every watched file to its state at scan time.
+type Snapshot map[string]fileState
+
+// Scan records every regular file under dirs (recursively) and every file
+// matching globs. Missing directories are skipped so an input that does not
+// exist yet shows up as a change once it is created.
+func Scan(dirs, globs []string) (Snapshot, error) {
+	snap := make(Snapshot)
+	add := func(path string, info fs.FileInfo) {
+		snap[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+	}
+
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.Type().IsRegular() {
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				add(path, info)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scanning %s: %w", dir, err)
+		}
+	}
+
+	for _, glob := range globs {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", glob, err)
+		}
+		for _, path := range matches {
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				add(path, info)
+			}
+		}
+	}
+	return snap, nil
+}
+
+// Changed lists files that were added, removed or modified since prev, sorted.
+func (s Snapshot) Changed(prev Snapshot) []string {
+	var changed []string
+	for path, state := range s {
+		if old, ok := prev[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := s[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Poll rescans every interval and calls onChange with the changed files until
+// ctx is cancelled. The first scan only establishes the baseline.
+func Poll(ctx context.Context, interval time.Duration, scan func() (Snapshot, error), onChange func(changed []string)) error {
+	prev, err := scan()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next, err := scan()
+		if err != nil {
+			return err
+		}
+		if changed := next.Changed(prev); len(changed) > 0 {
+			onChange(changed)
+		}
+		prev = next
+	}
+}
+
+// Step is one command in a watch cycle (build, solve, test).
+type Step struct {
+	Name string
+	Args []string
+
+	// Summarize extracts a short detail line from the command's output,
+	// and may mark the step failed even when the command exited cleanly.
+	Summarize func(output []byte) (detail string, ok bool)
+}
+
+// Result is the outcome of running a Step.
+type Result struct {
+	Step    string
+	OK      bool
+	Detail  string
+	Elapsed time.Duration
+	Output  []byte
+}
+
+// RunSteps runs steps in order, stopping after the first failure since later
+// steps (running the solver, its tests) depend on the earlier ones (the build).
+func RunSteps(ctx context.Context, steps []Step) []Result {
+	var results []Result
+	for _, step := range steps {
+		start := time.Now()
+		cmd := exec.CommandContext(ctx, step.Args[0], step.Args[1:]...)
+		output, err := cmd.CombinedOutput()
+
+		r := Result{Step: step.Name, OK: err == nil, Elapsed: time.Since(start), Output: output}
+		if step.Summarize != nil {
+			detail, ok := step.Summarize(output)
+			r.Detail = detail
+			r.OK = r.OK && ok
+		}
+		if err != nil && r.Detail == "" {
+			r.Detail = err.Error()
+		}
+		results = append(results, r)
+
+		if !r.OK {
+			break
+		}
+	}
+	return results
+}
+
+// PrintSummary writes one line per step, plus the output of a failed step.
+func PrintSummary(w io.Writer, results []Result) {
+	for _, r := range results {
+		mark := "✅"
+		if !r.OK {
+			mark = "❌"
+		}
+		line := fmt.Sprintf("%s %-6s %v", mark, r.Step, r.Elapsed.Round(time.Millisecond))
+		if r.Detail != "" {
+			line += "  " + r.Detail
+		}
+		fmt.Fprintln(w, line)
+
+		if !r.OK {
+			w.Write(bytes.TrimRight(r.Output, "\n"))
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// SolverLines keeps only the runner's result lines (✅/❌), dropping any
+// progress output the solver prints. A ❌ line fails the step.
+func SolverLines(output []byte) (string, bool) {
+	var lines []string
+	ok := true
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "✅"):
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(line, "✅")))
+		case strings.HasPrefix(line, "❌"):
+			lines = append(lines, strings.TrimSpace(strings.TrimPrefix(line, "❌")))
+			ok = false
+		}
+	}
+	return strings.Join(lines, " | "), ok
+}
+
+// TestCounts summarises `go test -v` output as pass/fail/skip counts of the
+// top-level tests (subtests are indented and not counted separately).
+func TestCounts(output []byte) (string, bool) {
+	var passed, failed, skipped int
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "--- PASS:"):
+			passed++
+		case strings.HasPrefix(line, "--- FAIL:"):
+			failed++
+		case strings.HasPrefix(line, "--- SKIP:"):
+			skipped++
+		}
+	}
+	return fmt.Sprintf("%d passed, %d failed, %d skipped", passed, failed, skipped), failed == 0
+}
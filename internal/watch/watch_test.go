@@ -0,0 +1,86 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSnapshotChanged(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "pkg", "part1.go")
+	input := filepath.Join(dir, "day1_input.txt")
+	os.MkdirAll(filepath.Dir(src), 0o755)
+	os.WriteFile(src, []byte("package day1\n"), 0o644)
+
+	scan := func() Snapshot {
+		t.Helper()
+		snap, err := Scan([]string{filepath.Join(dir, "pkg"), filepath.Join(dir, "missing")}, []string{filepath.Join(dir, "day1_*.txt")})
+		if err != nil {
+			t.Fatalf("Scan: %v", err)
+		}
+		return snap
+	}
+
+	base := scan()
+	if changed := scan().Changed(base); len(changed) != 0 {
+		t.Fatalf("no edits but Changed = %v", changed)
+	}
+
+	os.WriteFile(input, []byte("L5\n"), 0o644)
+	os.WriteFile(src, []byte("package day1\n\n// edited\n"), 0o644)
+	after := scan()
+	if changed := after.Changed(base); !slices.Equal(changed, []string{input, src}) {
+		t.Errorf("after edits Changed = %v, want [%s %s]", changed, input, src)
+	}
+
+	os.Remove(input)
+	if changed := scan().Changed(after); !slices.Equal(changed, []string{input}) {
+		t.Errorf("after removal Changed = %v, want [%s]", changed, input)
+	}
+}
+
+func TestPollReportsChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "part2.go")
+	os.WriteFile(path, []byte("v1"), 0o644)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	scans := 0
+	scan := func() (Snapshot, error) {
+		scans++
+		if scans == 2 {
+			os.WriteFile(path, []byte("version 2"), 0o644)
+		}
+		return Scan([]string{dir}, nil)
+	}
+
+	var got []string
+	err := Poll(ctx, time.Millisecond, scan, func(changed []string) {
+		got = changed
+		cancel()
+	})
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if !slices.Equal(got, []string{path}) {
+		t.Errorf("onChange got %v, want [%s]", got, path)
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	detail, ok := SolverLines([]byte("🎄 header\nprogress...\n✅ Day 1 Part 1: 3 (1ms)\n❌ Day 1 Part 2: boom\n"))
+	if ok || detail != "Day 1 Part 1: 3 (1ms) | Day 1 Part 2: boom" {
+		t.Errorf("SolverLines = %q, %v", detail, ok)
+	}
+
+	detail, ok = TestCounts([]byte("=== RUN TestA\n--- PASS: TestA (0.00s)\n--- SKIP: TestB (0.00s)\n    --- PASS: TestC/sub (0.00s)\n--- FAIL: TestC (0.00s)\n"))
+	if ok || detail != "1 passed, 1 failed, 1 skipped" {
+		t.Errorf("TestCounts = %q, %v", detail, ok)
+	}
+}